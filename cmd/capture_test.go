@@ -0,0 +1,318 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"specfirst/internal/app"
+	"specfirst/internal/repository"
+	"specfirst/internal/utils"
+)
+
+// setupCaptureWorkspace creates a workspace whose harness echoes the prompt back.
+func setupCaptureWorkspace(t *testing.T, protocol string, templates map[string]string) string {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	tmp := t.TempDir()
+	if err := os.Chdir(tmp); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = os.Chdir(wd)
+		protocolFlag = ""
+		stageCapture = false
+		stageForce = false
+	})
+
+	for _, dir := range []string{repository.ProtocolsPath(), repository.TemplatesPath()} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(repository.ConfigPath(), []byte("project_name: capture\nprotocol: capture\nharness: cat\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(repository.ProtocolsPath("capture.yaml"), []byte(protocol), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range templates {
+		if err := os.WriteFile(repository.TemplatesPath(name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	protocolFlag = ""
+	stageCapture = true
+	return tmp
+}
+
+func TestCaptureCompletesStage(t *testing.T) {
+	root := setupCaptureWorkspace(t, `name: capture
+version: "1"
+stages:
+  - id: notes
+    name: Notes
+    template: notes.md
+    outputs: [notes.md]
+    output:
+      format: markdown
+      sections: [Summary]
+`, map[string]string{"notes.md": "# Notes\n\n## Summary\nCaptured.\n"})
+
+	var out bytes.Buffer
+	if err := runStage(context.Background(), &out, "notes"); err != nil {
+		t.Fatalf("runStage: %v", err)
+	}
+
+	written, err := os.ReadFile(filepath.Join(root, "notes.md"))
+	if err != nil {
+		t.Fatalf("expected captured output file: %v", err)
+	}
+	if !strings.Contains(string(written), "## Summary") {
+		t.Fatalf("unexpected captured content: %q", written)
+	}
+
+	s, err := repository.LoadState(repository.StatePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.IsStageCompleted("notes") {
+		t.Fatalf("expected stage to be completed, got %v", s.CompletedStages)
+	}
+	if got, want := s.StageOutputs["notes"].PromptHash, utils.PromptHash(string(written)); got != want {
+		t.Fatalf("prompt hash = %s, want %s", got, want)
+	}
+}
+
+func TestCaptureRejectsContractViolation(t *testing.T) {
+	setupCaptureWorkspace(t, `name: capture
+version: "1"
+stages:
+  - id: notes
+    name: Notes
+    template: notes.md
+    outputs: [notes.md]
+    output:
+      format: markdown
+      sections: [Summary]
+`, map[string]string{"notes.md": "# Notes\n"})
+
+	var out bytes.Buffer
+	err := runStage(context.Background(), &out, "notes")
+	if err == nil || !strings.Contains(err.Error(), "Summary") {
+		t.Fatalf("expected contract violation error, got %v", err)
+	}
+
+	s, err := repository.LoadState(repository.StatePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.IsStageCompleted("notes") {
+		t.Fatalf("stage should not be completed after a contract violation")
+	}
+}
+
+func TestCaptureSplitsMultiFileResponse(t *testing.T) {
+	root := setupCaptureWorkspace(t, `name: capture
+version: "1"
+stages:
+  - id: design
+    name: Design
+    template: design.md
+    outputs: [design.md, "notes/*.md"]
+`, map[string]string{"design.md": "<file path=\"design.md\">\n# Design\n</file>\n<file path=\"notes/api.md\">\n# API\n</file>\n"})
+
+	var out bytes.Buffer
+	if err := runStage(context.Background(), &out, "design"); err != nil {
+		t.Fatalf("runStage: %v", err)
+	}
+
+	for rel, want := range map[string]string{"design.md": "# Design\n", "notes/api.md": "# API\n"} {
+		got, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(rel)))
+		if err != nil {
+			t.Fatalf("expected %s to be written: %v", rel, err)
+		}
+		if string(got) != want {
+			t.Fatalf("%s = %q, want %q", rel, got, want)
+		}
+	}
+
+	s, err := repository.LoadState(repository.StatePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if files := s.StageOutputs["design"].Files; len(files) != 2 {
+		t.Fatalf("expected 2 stored artifacts, got %v", files)
+	}
+}
+
+func TestCaptureRejectsUndeclaredResponseFile(t *testing.T) {
+	setupCaptureWorkspace(t, `name: capture
+version: "1"
+stages:
+  - id: design
+    name: Design
+    template: design.md
+    outputs: [design.md]
+`, map[string]string{"design.md": "<file path=\"other.md\">\n# Other\n</file>\n"})
+
+	var out bytes.Buffer
+	err := runStage(context.Background(), &out, "design")
+	if err == nil || !strings.Contains(err.Error(), "does not match stage outputs") {
+		t.Fatalf("expected undeclared output error, got %v", err)
+	}
+}
+
+func TestCaptureRecordsRun(t *testing.T) {
+	setupCaptureWorkspace(t, `name: capture
+version: "1"
+stages:
+  - id: notes
+    name: Notes
+    template: notes.md
+    outputs: [notes.md]
+`, map[string]string{"notes.md": "# Notes\n"})
+
+	var out bytes.Buffer
+	if err := runStage(context.Background(), &out, "notes"); err != nil {
+		t.Fatalf("runStage: %v", err)
+	}
+
+	runs, err := repository.ListRuns()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 1 {
+		t.Fatalf("expected 1 recorded run, got %d", len(runs))
+	}
+	run := runs[0]
+	if run.Stage != "notes" || run.Harness != "cat" || run.ExitCode != 0 || !run.Captured {
+		t.Fatalf("unexpected run record: %+v", run)
+	}
+
+	s, err := repository.LoadState(repository.StatePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.PromptHash != s.StageOutputs["notes"].PromptHash {
+		t.Fatalf("run prompt hash %s does not match completion hash %s", run.PromptHash, s.StageOutputs["notes"].PromptHash)
+	}
+
+	var shown bytes.Buffer
+	runsShowCmd.SetOut(&shown)
+	t.Cleanup(func() { runsShowCmd.SetOut(nil) })
+	if err := runsShowCmd.RunE(runsShowCmd, []string{run.ID}); err != nil {
+		t.Fatalf("runs show: %v", err)
+	}
+	if got := shown.String(); !strings.Contains(got, "--- prompt ---\n# Notes\n") || !strings.Contains(got, "--- response ---\n# Notes\n") {
+		t.Fatalf("unexpected runs show output:\n%s", got)
+	}
+}
+
+func TestCaptureLeavesExistingOutputOnFailure(t *testing.T) {
+	root := setupCaptureWorkspace(t, `name: capture
+version: "1"
+stages:
+  - id: notes
+    name: Notes
+    template: notes.md
+    outputs: [notes.md]
+    output:
+      format: markdown
+      sections: [Summary]
+`, map[string]string{"notes.md": "# Notes\n"})
+
+	existing := filepath.Join(root, "notes.md")
+	if err := os.WriteFile(existing, []byte("# Notes\n\n## Summary\nHand written.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	assertUntouched := func() {
+		t.Helper()
+		got, err := os.ReadFile(existing)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != "# Notes\n\n## Summary\nHand written.\n" {
+			t.Fatalf("existing output was overwritten: %q", got)
+		}
+		entries, err := os.ReadDir(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range entries {
+			if strings.Contains(e.Name(), ".capture-") {
+				t.Fatalf("temporary capture file left behind: %s", e.Name())
+			}
+		}
+	}
+
+	var out bytes.Buffer
+	err := runStage(context.Background(), &out, "notes")
+	if err == nil || !strings.Contains(err.Error(), "notes.md") || strings.Contains(err.Error(), ".capture-") {
+		t.Fatalf("expected a contract violation reported against notes.md, got %v", err)
+	}
+	assertUntouched()
+
+	// A completed stage is not captured again without --force.
+	application, err := app.Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := application.CompleteStage(context.Background(), "notes", []string{"notes.md"}, false, ""); err != nil {
+		t.Fatal(err)
+	}
+	err = runStage(context.Background(), &out, "notes")
+	if err == nil || !strings.Contains(err.Error(), "already completed") {
+		t.Fatalf("expected an already completed error, got %v", err)
+	}
+	assertUntouched()
+}
+
+func TestCaptureRecordsPromptSentToHarness(t *testing.T) {
+	setupCaptureWorkspace(t, `name: capture
+version: "1"
+stages:
+  - id: notes
+    name: Notes
+    template: notes.md
+    outputs: [notes.md]
+`, map[string]string{"notes.md": "# Notes\n\nA long prompt body.\n"})
+	stageMaxChars, stageFormat = 12, "json"
+	t.Cleanup(func() { stageMaxChars, stageFormat = 0, "text" })
+
+	var out bytes.Buffer
+	if err := runStage(context.Background(), &out, "notes"); err != nil {
+		t.Fatalf("runStage: %v", err)
+	}
+	runs, err := repository.ListRuns()
+	if err != nil || len(runs) != 1 {
+		t.Fatalf("expected 1 run, got %d (%v)", len(runs), err)
+	}
+	s, err := repository.LoadState(repository.StatePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runs[0].PromptHash != s.StageOutputs["notes"].PromptHash {
+		t.Fatalf("run prompt hash %s does not match completion hash %s", runs[0].PromptHash, s.StageOutputs["notes"].PromptHash)
+	}
+	sent, err := repository.LoadPrompt(runs[0].PromptHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(sent, "prompt body") {
+		t.Fatalf("expected the stored prompt to be the truncated one, got %q", sent)
+	}
+	entries, err := os.ReadDir(repository.GeneratedPath(repository.PromptsDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected a single stored prompt, got %d", len(entries))
+	}
+}
@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"fmt"
+
 	"specfirst/internal/app"
 
 	"github.com/spf13/cobra"
@@ -9,18 +11,62 @@ import (
 var checkCmd = &cobra.Command{
 	Use:   "check",
 	Short: "Run all non-blocking validations (lint, tasks, approvals, outputs)",
+	Long: `Run all non-blocking validations (lint, tasks, approvals, outputs).
+Use --format json|sarif|junit for CI: SARIF results carry rule IDs and file/line
+locations so they can be shown inline on pull requests.
+
+Every finding has a rule ID and a severity (error, warning or note). Use
+--fail-on error to fail only on errors, or --fail-on warning to also fail on
+warnings (the same as --fail-on-warnings).`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		failOnWarnings, _ := cmd.Flags().GetBool("fail-on-warnings")
+		failOn, _ := cmd.Flags().GetString("fail-on")
+		if failOn == "" && failOnWarnings {
+			failOn = app.SeverityWarning
+		}
+		if failOn != "" && severityRank(failOn) == 0 {
+			return fmt.Errorf("invalid --fail-on %q (use error, warning or note)", failOn)
+		}
 
 		application, err := app.Load(protocolFlag)
 		if err != nil {
 			return err
 		}
 
-		return application.Check(failOnWarnings)
+		findings := application.Check()
+		if err := writeFindings(cmd.OutOrStdout(), stageFormat, application, findings); err != nil {
+			return err
+		}
+		if failOn == "" {
+			return nil
+		}
+		failing := 0
+		for _, f := range findings {
+			if severityRank(f.Severity) >= severityRank(failOn) {
+				failing++
+			}
+		}
+		if failing > 0 {
+			return fmt.Errorf("check failed with %d finding(s) at severity %s or above", failing, failOn)
+		}
+		return nil
 	},
 }
 
+// severityRank orders finding severities; unknown severities rank 0.
+func severityRank(severity string) int {
+	switch severity {
+	case app.SeverityError:
+		return 3
+	case app.SeverityWarning:
+		return 2
+	case app.SeverityNote:
+		return 1
+	}
+	return 0
+}
+
 func init() {
-	checkCmd.Flags().Bool("fail-on-warnings", false, "exit with code 1 if warnings are found")
+	checkCmd.Flags().Bool("fail-on-warnings", false, "exit with code 1 if warnings or errors are found")
+	checkCmd.Flags().String("fail-on", "", "exit with code 1 if findings at or above this severity are found (error, warning, note)")
 }
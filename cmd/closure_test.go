@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"specfirst/internal/app"
+	"specfirst/internal/repository"
+)
+
+func TestTerminalStageClosesWorkflow(t *testing.T) {
+	root := setupCaptureWorkspace(t, `name: capture
+version: "1"
+stages:
+  - id: spec
+    name: Spec
+    template: spec.md
+    outputs: [spec.md]
+  - id: release
+    name: Release
+    template: release.md
+    depends_on: [spec]
+    outputs: [release.md]
+    terminal: true
+approvals:
+  - stage: spec
+    role: architect
+`, map[string]string{"spec.md": "Spec", "release.md": "Release"})
+	stageCapture = false
+	if err := os.WriteFile(repository.ConfigPath(), []byte("project_name: capture\nprotocol: capture\narchive_on_close: true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	complete := func(stageID string, force bool) error {
+		t.Helper()
+		file := stageID + ".md"
+		if err := os.WriteFile(filepath.Join(root, file), []byte(stageID), 0644); err != nil {
+			t.Fatal(err)
+		}
+		application, err := app.Load("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		application.State.SpecVersion = "1.0"
+		return application.CompleteStage(context.Background(), stageID, []string{file}, force, "")
+	}
+	if err := complete("spec", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := complete("release", false); err != nil {
+		t.Fatal(err)
+	}
+
+	application, err := app.Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed := application.State.Closed
+	if closed == nil || closed.Stage != "release" || closed.Archive != "1.0" {
+		t.Fatalf("expected workflow closed and archived by release, got %+v", closed)
+	}
+	if len(closed.Issues) != 1 || !strings.Contains(closed.Issues[0], "spec (role: architect)") {
+		t.Fatalf("expected missing approval to be recorded on close, got %v", closed.Issues)
+	}
+	if _, err := os.Stat(filepath.Join(repository.ArchivesPath("1.0"), "metadata.json")); err != nil {
+		t.Fatalf("expected archive 1.0: %v", err)
+	}
+	archived, err := repository.LoadState(repository.ArchivesPath("1.0", "state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if archived.Closed == nil || archived.Closed.Archive != "1.0" {
+		t.Fatalf("expected the archived state to record the archive, got %+v", archived.Closed)
+	}
+
+	var out bytes.Buffer
+	statusCmd.SetOut(&out)
+	t.Cleanup(func() { statusCmd.SetOut(nil) })
+	if err := statusCmd.RunE(statusCmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Workflow: CLOSED by terminal stage release") {
+		t.Fatalf("expected status to report closure, got:\n%s", out.String())
+	}
+
+	if err := complete("spec", true); err == nil || !strings.Contains(err.Error(), "reopen") {
+		t.Fatalf("expected completion to be refused while closed, got %v", err)
+	}
+
+	out.Reset()
+	reopenCmd.SetOut(&out)
+	t.Cleanup(func() { reopenCmd.SetOut(nil) })
+	if err := reopenCmd.RunE(reopenCmd, nil); err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if err := reopenCmd.RunE(reopenCmd, nil); err == nil {
+		t.Fatal("expected reopening an open workflow to fail")
+	}
+	if err := complete("spec", true); err != nil {
+		t.Fatalf("expected completion after reopen, got %v", err)
+	}
+}
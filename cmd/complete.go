@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -117,10 +119,19 @@ var completeCmd = &cobra.Command{
 
 		// Delegate to App (pass context for graceful shutdown)
 		if err := application.CompleteStage(cmd.Context(), stageID, outputFiles, completeForce, promptFile); err != nil {
+			var contractErr *app.ContractError
+			if errors.As(err, &contractErr) && stageFormat == "json" {
+				data, jsonErr := json.MarshalIndent(contractErr, "", "  ")
+				if jsonErr != nil {
+					return jsonErr
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), string(data))
+			}
 			return err
 		}
 
 		fmt.Fprintf(cmd.OutOrStdout(), "Completed stage %s\n", stageID)
+		reportClosure(cmd.OutOrStdout(), cmd.ErrOrStderr(), application)
 		return nil
 	},
 }
@@ -130,6 +141,22 @@ func init() {
 	completeCmd.Flags().BoolVar(&completeForce, "force", false, "force overwrite of existing stage completion")
 }
 
+// reportClosure prints the outcome of closing the workflow when the stage just
+// completed was terminal.
+func reportClosure(out, errOut io.Writer, application *app.Application) {
+	if !application.State.IsClosed() {
+		return
+	}
+	closed := application.State.Closed
+	fmt.Fprintf(out, "Workflow closed by terminal stage %s\n", closed.Stage)
+	for _, issue := range closed.Issues {
+		fmt.Fprintf(errOut, "Warning: %s\n", issue)
+	}
+	if closed.Archive != "" {
+		fmt.Fprintf(out, "Archived version %s\n", closed.Archive)
+	}
+}
+
 func isOutputMatch(patterns []string, file string) bool {
 	for _, pattern := range patterns {
 		if repository.MatchOutputPattern(pattern, file) {
@@ -27,14 +27,8 @@ Use --warn-only to report missing stages or approvals without failing the comman
 		if err != nil {
 			return err
 		}
-		// Ensure state initialized? App load does it.
 
-		missing := []string{}
-		for _, stage := range application.Protocol.Stages {
-			if !application.State.IsStageCompleted(stage.ID) {
-				missing = append(missing, stage.ID)
-			}
-		}
+		missing, missingApprovalRecords := application.SpecCompletionIssues()
 		if len(missing) > 0 {
 			err := fmt.Errorf("spec is not complete, missing stages: %v", missing)
 			if warnOnly {
@@ -44,20 +38,6 @@ Use --warn-only to report missing stages or approvals without failing the comman
 			}
 		}
 
-		// Convert protocol approvals to state approvals check
-		// Original code logic: `missingApprovalRecords := state.MissingApprovals(approvals, s)`
-		// I must implement `MissingApprovals` logic here or in App/Domain.
-		// Let's implement inline as it's simple loop.
-
-		var missingApprovalRecords []string
-		for _, a := range application.Protocol.Approvals {
-			// Check if stage is completed first? Usually approvals needed for completed stages logic?
-			// The original logic checked if approvals were present for required approvals.
-			if !application.State.HasAttestation(a.Stage, a.Role, "approved") {
-				missingApprovalRecords = append(missingApprovalRecords, fmt.Sprintf("%s (role: %s)", a.Stage, a.Role))
-			}
-		}
-
 		if len(missingApprovalRecords) > 0 {
 			err := fmt.Errorf("spec is not approved, missing approvals: %v", missingApprovalRecords)
 			if warnOnly {
@@ -70,6 +50,11 @@ Use --warn-only to report missing stages or approvals without failing the comman
 		if len(missing) == 0 && len(missingApprovalRecords) == 0 {
 			fmt.Fprintln(cmd.OutOrStdout(), "All stages completed.")
 		}
+		for _, stage := range application.Protocol.Stages {
+			if skip, ok := application.State.Skipped[stage.ID]; ok {
+				fmt.Fprintf(cmd.OutOrStdout(), "Skipped %s: %s\n", stage.ID, skip.Reason)
+			}
+		}
 
 		if archiveFlag {
 			if version == "" {
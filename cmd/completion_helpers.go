@@ -38,6 +38,13 @@ func archiveVersionCompletions(cmd *cobra.Command, args []string, toComplete str
 	return filterPrefix(loadArchiveVersions(), toComplete), cobra.ShellCompDirectiveNoFileComp
 }
 
+func runIDCompletions(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return filterPrefix(loadRunIDs(), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
 func attestRoleCompletions(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	if len(args) == 0 {
 		return nil, cobra.ShellCompDirectiveNoFileComp
@@ -76,6 +83,19 @@ func loadStageIDs() []string {
 	return stageIDs
 }
 
+func loadHarnessNames() []string {
+	cfg, err := repository.LoadConfig(repository.ConfigPath())
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(cfg.Harnesses))
+	for name := range cfg.Harnesses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func loadProtocolNames() []string {
 	entries, err := os.ReadDir(repository.ProtocolsPath())
 	if err != nil {
@@ -118,6 +138,18 @@ func loadArchiveVersions() []string {
 	return versions
 }
 
+func loadRunIDs() []string {
+	runs, err := repository.ListRuns()
+	if err != nil {
+		return nil
+	}
+	ids := make([]string, 0, len(runs))
+	for _, run := range runs {
+		ids = append(ids, run.ID)
+	}
+	return ids
+}
+
 func filterPrefix(values []string, prefix string) []string {
 	if prefix == "" {
 		return values
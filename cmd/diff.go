@@ -3,41 +3,67 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
+	"specfirst/internal/app"
+	"specfirst/internal/domain"
 	"specfirst/internal/engine/prompt"
 	"specfirst/internal/engine/system"
 	"specfirst/internal/repository"
 )
 
+var (
+	diffStage     string
+	diffIteration int
+)
+
 var diffCmd = &cobra.Command{
-	Use:   "diff <old-spec> <new-spec>",
+	Use:   "diff <old-spec> <new-spec> | --stage <id> [--iteration N]",
 	Short: "Generate a prompt analyzing spec changes",
 	Long: `Generate a prompt that describes the delta between two specification files.
 
 This command helps evaluate behavioral differences, backward compatibility risks,
 required code changes, and tests that must be updated when specifications change.
 
+With --stage, compares iteration N of a repeatable stage with iteration N-1
+(N defaults to the latest iteration).
+
 The output is a structured prompt suitable for AI assistants or human reviewers.`,
-	Args: cobra.ExactArgs(2),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if diffStage != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(2)(cmd, args)
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		oldPath := args[0]
-		newPath := args[1]
+		var before, after string
+		if diffStage != "" {
+			var err error
+			before, after, err = iterationDiffInputs(diffStage, diffIteration)
+			if err != nil {
+				return err
+			}
+		} else {
+			oldPath := args[0]
+			newPath := args[1]
 
-		oldContent, err := os.ReadFile(oldPath)
-		if err != nil {
-			return fmt.Errorf("reading old spec %s: %w", oldPath, err)
-		}
+			oldContent, err := os.ReadFile(oldPath)
+			if err != nil {
+				return fmt.Errorf("reading old spec %s: %w", oldPath, err)
+			}
 
-		newContent, err := os.ReadFile(newPath)
-		if err != nil {
-			return fmt.Errorf("reading new spec %s: %w", newPath, err)
+			newContent, err := os.ReadFile(newPath)
+			if err != nil {
+				return fmt.Errorf("reading new spec %s: %w", newPath, err)
+			}
+			before, after = string(oldContent), string(newContent)
 		}
 
 		promptStr, err := system.Render("change-impact.md", system.DiffData{
-			SpecBefore: string(oldContent),
-			SpecAfter:  string(newContent),
+			SpecBefore: before,
+			SpecAfter:  after,
 		})
 		if err != nil {
 			return fmt.Errorf("rendering diff prompt: %w", err)
@@ -58,3 +84,62 @@ The output is a structured prompt suitable for AI assistants or human reviewers.
 		return err
 	},
 }
+
+func init() {
+	diffCmd.Flags().StringVar(&diffStage, "stage", "", "compare iterations of a repeatable stage instead of two files")
+	diffCmd.Flags().IntVar(&diffIteration, "iteration", 0, "with --stage, the iteration to compare against its predecessor (default: latest)")
+}
+
+// iterationDiffInputs returns the artifacts of iteration n-1 and n of a stage.
+func iterationDiffInputs(stageID string, n int) (string, string, error) {
+	application, err := app.Load(protocolFlag)
+	if err != nil {
+		return "", "", err
+	}
+	stage, ok := application.Protocol.StageByID(stageID)
+	if !ok {
+		return "", "", fmt.Errorf("unknown stage: %s", stageID)
+	}
+	if !stage.Repeatable {
+		return "", "", fmt.Errorf("stage %s is not repeatable", stageID)
+	}
+
+	current, err := application.Iteration(stageID, n)
+	if err != nil {
+		return "", "", err
+	}
+	if current.Iteration < 2 {
+		return "", "", fmt.Errorf("stage %s iteration %d has no previous iteration to compare with", stageID, current.Iteration)
+	}
+	previous, err := application.Iteration(stageID, current.Iteration-1)
+	if err != nil {
+		return "", "", err
+	}
+
+	before, err := iterationText(stageID, previous)
+	if err != nil {
+		return "", "", err
+	}
+	after, err := iterationText(stageID, current)
+	if err != nil {
+		return "", "", err
+	}
+	return before, after, nil
+}
+
+// iterationText joins an iteration's artifacts; multiple files are wrapped in
+// <artifact> tags so the comparison keeps them apart.
+func iterationText(stageID string, iteration domain.StageOutput) (string, error) {
+	artifacts, err := app.IterationArtifacts(stageID, iteration)
+	if err != nil {
+		return "", err
+	}
+	if len(artifacts) == 1 {
+		return artifacts[0].Content, nil
+	}
+	var b strings.Builder
+	for _, a := range artifacts {
+		fmt.Fprintf(&b, "<artifact name=\"%s\">\n%s\n</artifact>\n", a.Name, strings.TrimRight(a.Content, "\n"))
+	}
+	return b.String(), nil
+}
@@ -2,8 +2,12 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
 
+	"specfirst/internal/app"
+	"specfirst/internal/domain"
 	"specfirst/internal/repository"
 
 	"github.com/spf13/cobra"
@@ -13,8 +17,57 @@ var (
 	epistemicOwner   string
 	epistemicTags    string
 	epistemicContext string
+	epistemicStage   string
+	epistemicSource  string
+	epistemicBy      string
 )
 
+// ledgerUser returns --by, defaulting to $USER.
+func ledgerUser() string {
+	if epistemicBy != "" {
+		return epistemicBy
+	}
+	if user := os.Getenv("USER"); user != "" {
+		return user
+	}
+	return os.Getenv("USERNAME")
+}
+
+// ledgerProvenance attributes a new ledger entry to --stage (default: the
+// current stage), --source (artifact[:line]) and --by. An explicit --stage
+// must be a stage of the protocol.
+func ledgerProvenance(s domain.State) (domain.Provenance, error) {
+	prov := domain.Provenance{
+		Stage:          epistemicStage,
+		SourceArtifact: epistemicSource,
+		CreatedBy:      ledgerUser(),
+	}
+	if prov.Stage == "" {
+		prov.Stage = s.CurrentStage
+	} else {
+		application, err := app.Load(protocolFlag)
+		if err != nil {
+			return prov, err
+		}
+		if _, ok := application.Protocol.StageByID(prov.Stage); !ok {
+			return prov, fmt.Errorf("unknown stage: %s", prov.Stage)
+		}
+	}
+	if i := strings.LastIndex(epistemicSource, ":"); i > 0 {
+		if line, err := strconv.Atoi(epistemicSource[i+1:]); err == nil {
+			prov.SourceArtifact, prov.SourceLine = epistemicSource[:i], line
+		}
+	}
+	return prov, nil
+}
+
+// addProvenanceFlags registers the attribution flags of an add command.
+func addProvenanceFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&epistemicStage, "stage", "", "stage that raised the entry (defaults to the current stage)")
+	cmd.Flags().StringVar(&epistemicSource, "source", "", "source artifact, optionally with a line (path[:line])")
+	cmd.Flags().StringVar(&epistemicBy, "by", "", "who is recording the entry (defaults to $USER)")
+}
+
 // -- Assume --
 
 var assumeCmd = &cobra.Command{
@@ -32,7 +85,11 @@ var assumeAddCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
-		id := s.AddAssumption(args[0], epistemicOwner)
+		prov, err := ledgerProvenance(s)
+		if err != nil {
+			return err
+		}
+		id := s.AddAssumption(args[0], epistemicOwner, prov)
 		if err := repository.SaveState(path, s); err != nil {
 			return err
 		}
@@ -78,7 +135,11 @@ var questionAddCmd = &cobra.Command{
 		if epistemicTags != "" {
 			tags = strings.Split(epistemicTags, ",")
 		}
-		id := s.AddOpenQuestion(args[0], tags, epistemicContext)
+		prov, err := ledgerProvenance(s)
+		if err != nil {
+			return err
+		}
+		id := s.AddOpenQuestion(args[0], tags, epistemicContext, prov)
 		if err := repository.SaveState(path, s); err != nil {
 			return err
 		}
@@ -121,7 +182,11 @@ var decisionAddCmd = &cobra.Command{
 			return err
 		}
 		// In a real implementation we might want flags for rationale/alternatives
-		id := s.AddDecision(args[0], "No rationale provided via CLI yet", nil)
+		prov, err := ledgerProvenance(s)
+		if err != nil {
+			return err
+		}
+		id := s.AddDecision(args[0], "No rationale provided via CLI yet", nil, prov)
 		if err := repository.SaveState(path, s); err != nil {
 			return err
 		}
@@ -151,7 +216,14 @@ var riskAddCmd = &cobra.Command{
 		if len(args) > 1 {
 			severity = args[1]
 		}
-		id := s.AddRisk(args[0], severity)
+		if err := domain.CheckValue("severity", severity, domain.Levels); err != nil {
+			return err
+		}
+		prov, err := ledgerProvenance(s)
+		if err != nil {
+			return err
+		}
+		id := s.AddRisk(args[0], severity, prov)
 		if err := repository.SaveState(path, s); err != nil {
 			return err
 		}
@@ -199,6 +271,11 @@ func init() {
 
 	// Decision
 	decisionCmd.AddCommand(decisionAddCmd)
+	for _, c := range []*cobra.Command{decisionExportCmd, decisionImportCmd} {
+		c.Flags().StringVar(&decisionADRDir, "adr", "docs/adr", "ADR directory, relative to the project root")
+		decisionCmd.AddCommand(c)
+	}
+	decisionImportCmd.Flags().StringVar(&epistemicBy, "by", "", "who is importing the decisions (defaults to $USER)")
 
 	// Risk
 	riskCmd.AddCommand(riskAddCmd)
@@ -206,12 +283,32 @@ func init() {
 	// Dispute
 	disputeCmd.AddCommand(disputeAddCmd)
 
+	for _, add := range []*cobra.Command{assumeAddCmd, questionAddCmd, decisionAddCmd, riskAddCmd} {
+		addProvenanceFlags(add)
+	}
+
 	// Lifecycle
 	assumeCmd.AddCommand(assumeCloseCmd)
 	questionCmd.AddCommand(questionResolveCmd)
 	decisionCmd.AddCommand(decisionUpdateCmd)
+	questionCmd.AddCommand(questionDeferCmd)
 	riskCmd.AddCommand(riskMitigateCmd)
 	disputeCmd.AddCommand(disputeResolveCmd)
+	disputePositionCmd.AddCommand(disputePositionAddCmd)
+	disputeCmd.AddCommand(disputePositionCmd)
+	confidenceCmd.AddCommand(confidenceSetCmd)
+
+	// Editing
+	parents := map[string]*cobra.Command{
+		domain.KindAssumption: assumeCmd,
+		domain.KindQuestion:   questionCmd,
+		domain.KindDecision:   decisionCmd,
+		domain.KindRisk:       riskCmd,
+		domain.KindDispute:    disputeCmd,
+	}
+	for kind, parent := range parents {
+		parent.AddCommand(ledgerEntryCmds(kind)...)
+	}
 }
 
 // Lifecycle Commands
@@ -230,7 +327,10 @@ var assumeCloseCmd = &cobra.Command{
 		if status == "" {
 			return fmt.Errorf("status is required")
 		}
-		if !s.CloseAssumption(args[0], status) {
+		if err := domain.CheckValue("assumption status", status, domain.LedgerStatuses[domain.KindAssumption][1:]); err != nil {
+			return err
+		}
+		if !s.CloseAssumption(args[0], status, ledgerUser()) {
 			return fmt.Errorf("assumption %s not found", args[0])
 		}
 		return repository.SaveState(path, s)
@@ -251,7 +351,7 @@ var questionResolveCmd = &cobra.Command{
 		if answer == "" {
 			return fmt.Errorf("answer is required")
 		}
-		if !s.ResolveOpenQuestion(args[0], answer) {
+		if !s.ResolveOpenQuestion(args[0], answer, ledgerUser()) {
 			return fmt.Errorf("question %s not found", args[0])
 		}
 		return repository.SaveState(path, s)
@@ -272,7 +372,10 @@ var decisionUpdateCmd = &cobra.Command{
 		if status == "" {
 			return fmt.Errorf("status is required")
 		}
-		if !s.UpdateDecision(args[0], status) {
+		if err := domain.CheckValue("decision status", status, domain.LedgerStatuses[domain.KindDecision]); err != nil {
+			return err
+		}
+		if !s.UpdateDecision(args[0], status, ledgerUser()) {
 			return fmt.Errorf("decision %s not found", args[0])
 		}
 		return repository.SaveState(path, s)
@@ -297,7 +400,10 @@ var riskMitigateCmd = &cobra.Command{
 		if status == "" {
 			status = "mitigated"
 		}
-		if !s.MitigateRisk(args[0], mitigation, status) {
+		if err := domain.CheckValue("risk status", status, domain.LedgerStatuses[domain.KindRisk][1:]); err != nil {
+			return err
+		}
+		if !s.MitigateRisk(args[0], mitigation, status, ledgerUser()) {
 			return fmt.Errorf("risk %s not found", args[0])
 		}
 		return repository.SaveState(path, s)
@@ -314,7 +420,8 @@ var disputeResolveCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
-		if !s.ResolveDispute(args[0]) {
+		outcome, _ := cmd.Flags().GetString("outcome")
+		if !s.ResolveDispute(args[0], outcome) {
 			return fmt.Errorf("dispute %s not found", args[0])
 		}
 		return repository.SaveState(path, s)
@@ -324,8 +431,208 @@ var disputeResolveCmd = &cobra.Command{
 func init() {
 	// Flags for lifecycle
 	assumeCloseCmd.Flags().String("status", "validated", "Status: validated|invalidated")
+	questionDeferCmd.Flags().String("reason", "", "Why the question is deferred")
 	questionResolveCmd.Flags().String("answer", "", "Answer to the question")
-	decisionUpdateCmd.Flags().String("status", "accepted", "New status")
+	decisionUpdateCmd.Flags().String("status", "accepted", "New status: proposed|accepted|reversed")
 	riskMitigateCmd.Flags().String("mitigation", "", "Mitigation plan")
-	riskMitigateCmd.Flags().String("status", "mitigated", "New status")
+	riskMitigateCmd.Flags().String("status", "mitigated", "New status: mitigated|accepted")
+	disputeResolveCmd.Flags().String("outcome", "", "How the dispute was resolved")
+	disputePositionAddCmd.Flags().StringVar(&epistemicOwner, "owner", "", "Who holds the position (defaults to $USER)")
+	confidenceSetCmd.Flags().StringVar(&epistemicStage, "stage", "", "Set the confidence of a stage instead of the overall confidence")
+	for _, update := range []*cobra.Command{assumeCloseCmd, questionResolveCmd, questionDeferCmd, decisionUpdateCmd, riskMitigateCmd} {
+		update.Flags().StringVar(&epistemicBy, "by", "", "who is making the change (defaults to $USER)")
+	}
+}
+
+var questionDeferCmd = &cobra.Command{
+	Use:   "defer [id]",
+	Short: "Defer an open question",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := repository.StatePath()
+		s, err := repository.LoadState(path)
+		if err != nil {
+			return err
+		}
+		reason, _ := cmd.Flags().GetString("reason")
+		if !s.DeferOpenQuestion(args[0], reason, ledgerUser()) {
+			return fmt.Errorf("question %s not found", args[0])
+		}
+		return repository.SaveState(path, s)
+	},
+}
+
+var disputePositionCmd = &cobra.Command{
+	Use:   "position",
+	Short: "Manage the positions in a dispute",
+}
+
+var disputePositionAddCmd = &cobra.Command{
+	Use:   "add [dispute-id] [claim]",
+	Short: "Record a position in a dispute",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := repository.StatePath()
+		s, err := repository.LoadState(path)
+		if err != nil {
+			return err
+		}
+		owner := epistemicOwner
+		if owner == "" {
+			owner = ledgerUser()
+		}
+		if !s.AddPosition(args[0], owner, args[1]) {
+			return fmt.Errorf("dispute %s not found", args[0])
+		}
+		return repository.SaveState(path, s)
+	},
+}
+
+// -- ADRs --
+
+var decisionADRDir string
+
+var decisionExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write accepted and reversed decisions as MADR files",
+	Long: `Write accepted decisions, and reversed decisions marked superseded, to the
+ADR directory as MADR files named NNNN-title.md. A decision keeps its file
+across exports; new decisions are numbered after the highest existing record.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		application, err := app.Load(protocolFlag)
+		if err != nil {
+			return err
+		}
+		written, err := application.ExportADRs(decisionADRDir)
+		if err != nil {
+			return err
+		}
+		out := cmd.OutOrStdout()
+		for _, file := range written {
+			fmt.Fprintf(out, "  wrote %s\n", file)
+		}
+		fmt.Fprintf(out, "Exported %d decision(s) to %s\n", len(written), decisionADRDir)
+		return nil
+	},
+}
+
+var decisionImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import decisions from existing ADR files",
+	Long: `Read the NNNN-title.md records in the ADR directory into the ledger as
+decisions. MADR and Nygard-style records are understood. Superseded,
+deprecated and rejected records become reversed decisions. Records already
+linked to a decision, or whose title matches one, are skipped.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		application, err := app.Load(protocolFlag)
+		if err != nil {
+			return err
+		}
+		imported, err := application.ImportADRs(decisionADRDir, ledgerUser())
+		if err != nil {
+			return err
+		}
+		out := cmd.OutOrStdout()
+		added, duplicates := 0, 0
+		for _, adr := range imported {
+			if adr.Duplicate {
+				duplicates++
+				fmt.Fprintf(out, "  = %s (%s, already recorded)\n", adr.Title, adr.File)
+				continue
+			}
+			added++
+			fmt.Fprintf(out, "  + [%s] %s (%s)\n", adr.ID, adr.Title, adr.File)
+		}
+		fmt.Fprintf(out, "Imported %d decision(s) from %s (%d duplicate(s) skipped)\n", added, decisionADRDir, duplicates)
+		return nil
+	},
+}
+
+// -- Confidence --
+
+var confidenceCmd = &cobra.Command{
+	Use:   "confidence",
+	Short: "Manage confidence levels",
+}
+
+var confidenceSetCmd = &cobra.Command{
+	Use:   "set [level]",
+	Short: "Set the overall or a stage's confidence (low, medium or high)",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := domain.CheckValue("confidence", args[0], domain.Levels); err != nil {
+			return err
+		}
+		if epistemicStage != "" {
+			application, err := app.Load(protocolFlag)
+			if err != nil {
+				return err
+			}
+			if _, ok := application.Protocol.StageByID(epistemicStage); !ok {
+				return fmt.Errorf("unknown stage: %s", epistemicStage)
+			}
+		}
+		path := repository.StatePath()
+		s, err := repository.LoadState(path)
+		if err != nil {
+			return err
+		}
+		s.SetConfidence(args[0], epistemicStage)
+		return repository.SaveState(path, s)
+	},
+}
+
+// ledgerEntryCmds returns the edit, rm and reopen commands for a kind of
+// ledger entry.
+func ledgerEntryCmds(kind string) []*cobra.Command {
+	edit := &cobra.Command{
+		Use:   "edit [id] [text]",
+		Short: fmt.Sprintf("Change the text of a %s", kind),
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return updateLedger(func(s *domain.State) bool {
+				return s.EditLedgerEntry(kind, args[0], args[1], ledgerUser())
+			}, kind, args[0])
+		},
+	}
+	rm := &cobra.Command{
+		Use:   "rm [id]",
+		Short: fmt.Sprintf("Delete a %s", kind),
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return updateLedger(func(s *domain.State) bool {
+				return s.RemoveLedgerEntry(kind, args[0])
+			}, kind, args[0])
+		},
+	}
+	reopen := &cobra.Command{
+		Use:   "reopen [id]",
+		Short: fmt.Sprintf("Return a %s to %s", kind, domain.LedgerStatuses[kind][0]),
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return updateLedger(func(s *domain.State) bool {
+				return s.ReopenLedgerEntry(kind, args[0], ledgerUser())
+			}, kind, args[0])
+		},
+	}
+	for _, c := range []*cobra.Command{edit, reopen} {
+		c.Flags().StringVar(&epistemicBy, "by", "", "who is making the change (defaults to $USER)")
+	}
+	return []*cobra.Command{edit, rm, reopen}
+}
+
+// updateLedger applies a change to the state and saves it, failing when the
+// entry does not exist.
+func updateLedger(change func(s *domain.State) bool, kind, id string) error {
+	path := repository.StatePath()
+	s, err := repository.LoadState(path)
+	if err != nil {
+		return err
+	}
+	if !change(&s) {
+		return fmt.Errorf("%s %s not found", kind, id)
+	}
+	return repository.SaveState(path, s)
 }
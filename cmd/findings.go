@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"specfirst/internal/app"
+)
+
+// writeFindings renders check/lint findings in the requested format: text
+// (grouped by category, with severity and rule ID), json, sarif (2.1.0) or junit.
+func writeFindings(out io.Writer, format string, application *app.Application, findings []app.Finding) error {
+	switch format {
+	case "text":
+		writeFindingsText(out, findings)
+		return nil
+	case "json":
+		report := struct {
+			Findings []app.Finding `json:"findings"`
+		}{findings}
+		if report.Findings == nil {
+			report.Findings = []app.Finding{}
+		}
+		return writeJSON(out, report)
+	case "sarif":
+		return writeJSON(out, sarifReport(application, findings))
+	case "junit":
+		return writeJUnit(out, findings)
+	default:
+		return fmt.Errorf("unsupported format %q (use text, json, sarif or junit)", format)
+	}
+}
+
+func writeJSON(out io.Writer, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(out, string(data))
+	return nil
+}
+
+func writeFindingsText(out io.Writer, findings []app.Finding) {
+	if len(findings) == 0 {
+		fmt.Fprintln(out, "No issues found.")
+		return
+	}
+	counts := make(map[string]int)
+	for _, f := range findings {
+		counts[f.Severity]++
+	}
+	fmt.Fprintf(out, "Findings: %d error(s), %d warning(s), %d note(s)\n", counts[app.SeverityError], counts[app.SeverityWarning], counts[app.SeverityNote])
+	for i := 0; i < len(findings); {
+		category := findings[i].Category
+		j := i
+		for j < len(findings) && findings[j].Category == category {
+			j++
+		}
+		fmt.Fprintf(out, "\n* %s (%d)\n", category, j-i)
+		for _, f := range findings[i:j] {
+			message := f.Message
+			if f.Line > 0 {
+				message = fmt.Sprintf("%s:%d:%d: %s", f.File, f.Line, f.Column, f.Message)
+			}
+			fmt.Fprintf(out, "  - %s: %s [%s]\n", f.Severity, message, f.RuleID)
+		}
+		i = j
+	}
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name    string      `json:"name"`
+	Version string      `json:"version"`
+	Rules   []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// sarifReport converts findings to SARIF. Code scanning requires every result
+// to have a location, so findings without a file point at the protocol.
+func sarifReport(application *app.Application, findings []app.Finding) sarifLog {
+	run := sarifRun{
+		Tool:    sarifTool{Driver: sarifDriver{Name: "specfirst", Version: version, Rules: []sarifRule{}}},
+		Results: []sarifResult{},
+	}
+	seen := make(map[string]bool)
+	for _, f := range findings {
+		if !seen[f.RuleID] {
+			seen[f.RuleID] = true
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: f.RuleID, ShortDescription: sarifMessage{Text: application.RuleDescription(f.RuleID)}})
+		}
+		result := sarifResult{RuleID: f.RuleID, Level: f.Severity, Message: sarifMessage{Text: f.Message}}
+		loc := sarifLocation{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: f.File}}}
+		if f.File == "" {
+			loc.PhysicalLocation.ArtifactLocation.URI = application.ProtocolFile()
+		} else if f.Line > 0 {
+			loc.PhysicalLocation.Region = &sarifRegion{StartLine: f.Line, StartColumn: f.Column}
+		}
+		result.Locations = []sarifLocation{loc}
+		run.Results = append(run.Results, result)
+	}
+	return sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}
+}
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnit reports each finding as a failed test case, one suite per category.
+func writeJUnit(out io.Writer, findings []app.Finding) error {
+	report := junitSuites{Tests: len(findings), Failures: len(findings)}
+	for _, f := range findings {
+		if n := len(report.Suites); n == 0 || report.Suites[n-1].Name != f.Category {
+			report.Suites = append(report.Suites, junitSuite{Name: f.Category})
+		}
+		suite := &report.Suites[len(report.Suites)-1]
+		name := f.RuleID
+		if f.Stage != "" {
+			name += " (" + f.Stage + ")"
+		}
+		location := f.File
+		if f.Line > 0 {
+			location = fmt.Sprintf("%s:%d:%d", f.File, f.Line, f.Column)
+		}
+		suite.Cases = append(suite.Cases, junitCase{
+			Name:      name,
+			Classname: "specfirst." + f.Category,
+			Failure:   &junitFailure{Message: f.Message, Type: f.Severity, Text: location},
+		})
+		suite.Tests++
+		suite.Failures++
+	}
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(out, xml.Header)
+	fmt.Fprintln(out, string(data))
+	return nil
+}
@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"specfirst/internal/app"
+)
+
+func TestCheckFormats(t *testing.T) {
+	root := setupCaptureWorkspace(t, `name: capture
+version: "1"
+stages:
+  - id: design
+    name: Design
+    template: design.md
+    outputs: [design.md]
+    output:
+      format: markdown
+      sections:
+        - path: Risks
+          non_empty: true
+`, map[string]string{"design.md": "# Context\n# Task\n# Assumptions\n"})
+	stageCapture = false
+	t.Cleanup(func() {
+		stageFormat = "text"
+		checkCmd.SetOut(nil)
+	})
+	if err := os.WriteFile(filepath.Join(root, "design.md"), []byte("# Design\n\n# Risks\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	application, err := app.Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := application.CompleteStage(context.Background(), "design", []string{"design.md"}, false, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(format string) string {
+		t.Helper()
+		var out bytes.Buffer
+		checkCmd.SetOut(&out)
+		stageFormat = format
+		if err := checkCmd.RunE(checkCmd, nil); err != nil {
+			t.Fatalf("check --format %s: %v", format, err)
+		}
+		return out.String()
+	}
+
+	var report struct {
+		Findings []app.Finding `json:"findings"`
+	}
+	if err := json.Unmarshal([]byte(run("json")), &report); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Findings) != 1 {
+		t.Fatalf("expected 1 finding, got %+v", report.Findings)
+	}
+	want := app.Finding{
+		Category: "Structure",
+		RuleID:   app.RuleMissingSection,
+		Severity: app.SeverityWarning,
+		Stage:    "design",
+		File:     ".specfirst/artifacts/design/design.md",
+		Line:     3,
+		Column:   1,
+		Message:  `section "Risks" is empty`,
+	}
+	if report.Findings[0] != want {
+		t.Fatalf("finding = %+v, want %+v", report.Findings[0], want)
+	}
+
+	var sarif sarifLog
+	if err := json.Unmarshal([]byte(run("sarif")), &sarif); err != nil {
+		t.Fatal(err)
+	}
+	result := sarif.Runs[0].Results[0]
+	if sarif.Version != "2.1.0" || result.RuleID != app.RuleMissingSection || result.Level != "warning" {
+		t.Fatalf("unexpected sarif result: %+v", result)
+	}
+	loc := result.Locations[0].PhysicalLocation
+	if loc.ArtifactLocation.URI != want.File || loc.Region == nil || loc.Region.StartLine != 3 {
+		t.Fatalf("unexpected sarif location: %+v", loc)
+	}
+	if rules := sarif.Runs[0].Tool.Driver.Rules; len(rules) != 1 || rules[0].ShortDescription.Text != "artifact is missing a required section" {
+		t.Fatalf("expected the rule description, got %+v", rules)
+	}
+
+	var junit junitSuites
+	if err := xml.Unmarshal([]byte(run("junit")), &junit); err != nil {
+		t.Fatal(err)
+	}
+	if junit.Failures != 1 || junit.Suites[0].Name != "Structure" {
+		t.Fatalf("unexpected junit report: %+v", junit)
+	}
+
+	if text := run("text"); !strings.Contains(text, `design.md:3:1: section "Risks" is empty`) {
+		t.Fatalf("unexpected text output:\n%s", text)
+	}
+}
+
+func TestCheckSeverityOverridesAndIgnoreMarkers(t *testing.T) {
+	root := setupCaptureWorkspace(t, `name: capture
+version: "1"
+lint:
+  severity:
+    missing-section: error
+stages:
+  - id: design
+    name: Design
+    template: design.md
+    outputs: [design.md]
+    output:
+      format: markdown
+      sections:
+        - path: Risks
+          non_empty: true
+        - path: Summary
+          non_empty: true
+`, map[string]string{"design.md": "# Context\n# Task\n# Assumptions\n"})
+	stageCapture = false
+	t.Cleanup(func() {
+		checkCmd.SetOut(nil)
+		_ = checkCmd.Flags().Set("fail-on", "")
+	})
+	artifact := "# Design\n\n<!-- specfirst-ignore: missing-section -->\n# Summary\n\n# Risks\n"
+	if err := os.WriteFile(filepath.Join(root, "design.md"), []byte(artifact), 0644); err != nil {
+		t.Fatal(err)
+	}
+	application, err := app.Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := application.CompleteStage(context.Background(), "design", []string{"design.md"}, false, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := checkCmd.Flags().Set("fail-on", "error"); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	checkCmd.SetOut(&out)
+	err = checkCmd.RunE(checkCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "1 finding(s) at severity error") {
+		t.Fatalf("expected check to fail on the error, got %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), `error: .specfirst/artifacts/design/design.md:6:1: section "Risks" is empty [missing-section]`) {
+		t.Fatalf("expected Risks finding as an error:\n%s", out.String())
+	}
+	if strings.Contains(out.String(), "Summary") {
+		t.Fatalf("expected Summary finding to be suppressed:\n%s", out.String())
+	}
+
+	// Config overrides win over the protocol.
+	config := "project_name: capture\nprotocol: capture\nharness: cat\nlint:\n  severity:\n    missing-section: note\n"
+	if err := os.WriteFile(filepath.Join(root, ".specfirst", "config.yaml"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := checkCmd.RunE(checkCmd, nil); err != nil {
+		t.Fatalf("expected notes not to fail --fail-on error: %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "note: ") {
+		t.Fatalf("expected the finding to be downgraded to a note:\n%s", out.String())
+	}
+}
+
+func TestCheckSarifLocatesFindingsWithoutFile(t *testing.T) {
+	setupCaptureWorkspace(t, `name: capture
+version: "1"
+stages:
+  - id: review
+    name: Review
+    intent: review
+    template: review.md
+    outputs: [review.md]
+`, map[string]string{"review.md": "# Context\n# Task\n# Assumptions\n"})
+	stageCapture = false
+	stageFormat = "sarif"
+	var out bytes.Buffer
+	checkCmd.SetOut(&out)
+	t.Cleanup(func() {
+		stageFormat = "text"
+		checkCmd.SetOut(nil)
+	})
+	if err := checkCmd.RunE(checkCmd, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var sarif sarifLog
+	if err := json.Unmarshal(out.Bytes(), &sarif); err != nil {
+		t.Fatal(err)
+	}
+	var found bool
+	for _, result := range sarif.Runs[0].Results {
+		if len(result.Locations) != 1 || result.Locations[0].PhysicalLocation.ArtifactLocation.URI == "" {
+			t.Fatalf("result without a location: %+v", result)
+		}
+		if result.RuleID == app.RuleReviewOutputs {
+			found = true
+			if uri := result.Locations[0].PhysicalLocation.ArtifactLocation.URI; uri != ".specfirst/protocols/capture.yaml" {
+				t.Fatalf("expected the protocol file as location, got %s", uri)
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("expected a %s result:\n%s", app.RuleReviewOutputs, out.String())
+	}
+}
+
+func TestCheckFailOnWarningsCatchesAmbiguousPrompts(t *testing.T) {
+	setupCaptureWorkspace(t, `name: capture
+version: "1"
+stages:
+  - id: design
+    name: Design
+    template: design.md
+`, map[string]string{"design.md": "# Context\n# Task\nAdd caching as appropriate.\n# Assumptions\n"})
+	stageCapture = false
+	var out bytes.Buffer
+	checkCmd.SetOut(&out)
+	if err := checkCmd.Flags().Set("fail-on-warnings", "true"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = checkCmd.Flags().Set("fail-on-warnings", "false")
+		checkCmd.SetOut(nil)
+	})
+
+	err := checkCmd.RunE(checkCmd, nil)
+	if err == nil || !strings.Contains(out.String(), "warning: Ambiguity (design)") {
+		t.Fatalf("expected --fail-on-warnings to fail on an ambiguous prompt, got %v:\n%s", err, out.String())
+	}
+}
@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"specfirst/internal/domain"
+	"specfirst/internal/repository"
+)
+
+// resolveHarness picks the harness profile for a stage. The --harness flag wins,
+// then the stage's harness key, then the config's harness setting. A name that
+// matches no profile but equals the config's harness is treated as a plain
+// command with harness_args. Returns ok=false when no harness is configured.
+func resolveHarness(cfg domain.Config, stage domain.Stage, override string) (domain.HarnessProfile, bool, error) {
+	name := override
+	if name == "" {
+		name = stage.Harness
+	}
+	if name == "" {
+		name = cfg.Harness
+	}
+	if name == "" {
+		return domain.HarnessProfile{}, false, nil
+	}
+
+	// Viper lowercases map keys, so profile names are case-insensitive.
+	if profile, ok := cfg.Harnesses[strings.ToLower(name)]; ok {
+		if strings.TrimSpace(profile.Command) == "" {
+			return domain.HarnessProfile{}, false, fmt.Errorf("harness profile %q has no command", name)
+		}
+		switch profile.Prompt {
+		case "", domain.PromptViaStdin, domain.PromptViaArgv, domain.PromptViaFile:
+		default:
+			return domain.HarnessProfile{}, false, fmt.Errorf("harness profile %q: invalid prompt delivery %q (must be stdin, argv, or file)", name, profile.Prompt)
+		}
+		return profile, true, nil
+	}
+
+	if name == cfg.Harness {
+		args, err := splitArgs(cfg.HarnessArgs)
+		if err != nil {
+			return domain.HarnessProfile{}, false, err
+		}
+		return domain.HarnessProfile{Command: cfg.Harness, Args: args}, true, nil
+	}
+
+	available := make([]string, 0, len(cfg.Harnesses))
+	for profileName := range cfg.Harnesses {
+		available = append(available, profileName)
+	}
+	sort.Strings(available)
+	if len(available) == 0 {
+		return domain.HarnessProfile{}, false, fmt.Errorf("unknown harness profile %q (no harnesses defined in config)", name)
+	}
+	return domain.HarnessProfile{}, false, fmt.Errorf("unknown harness profile %q (available: %s)", name, strings.Join(available, ", "))
+}
+
+// defaultRetryBackoff is the initial delay between harness attempts when a
+// profile enables retries without setting retry_backoff.
+const defaultRetryBackoff = 2 * time.Second
+
+// harnessKillDelay is how long an interrupted harness gets to exit before it is killed.
+const harnessKillDelay = 5 * time.Second
+
+// runHarness executes a harness profile, retrying failed attempts with
+// exponential backoff. Output is streamed to stream (when non-nil) and the
+// successful attempt's output is returned. Output from failed attempts is
+// saved under .specfirst/generated/harness/ for inspection.
+func runHarness(ctx context.Context, stageID string, profile domain.HarnessProfile, prompt string, stream io.Writer) (string, error) {
+	attempts := profile.Retries + 1
+	if attempts < 1 {
+		attempts = 1
+	}
+	backoff := profile.RetryBackoff
+	if backoff <= 0 {
+		backoff = defaultRetryBackoff
+	}
+
+	for attempt := 1; ; attempt++ {
+		var output bytes.Buffer
+		var stdout io.Writer = &output
+		if stream != nil {
+			stdout = io.MultiWriter(stream, &output)
+		}
+
+		err := runHarnessOnce(ctx, profile, prompt, stdout)
+		if err == nil {
+			return output.String(), nil
+		}
+
+		if output.Len() > 0 {
+			if path, saveErr := savePartialOutput(stageID, attempt, output.Bytes()); saveErr != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to save partial harness output: %v\n", saveErr)
+			} else {
+				fmt.Fprintf(os.Stderr, "Partial harness output saved to %s\n", path)
+			}
+		}
+
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("harness %s interrupted: %w", profile.Command, ctx.Err())
+		}
+		if attempt >= attempts || !isRetryableHarnessError(err) {
+			return "", err
+		}
+
+		wait := backoff * time.Duration(1<<(attempt-1))
+		fmt.Fprintf(os.Stderr, "Harness attempt %d/%d failed: %v; retrying in %s\n", attempt, attempts, err, wait)
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("harness %s interrupted: %w", profile.Command, ctx.Err())
+		case <-time.After(wait):
+		}
+	}
+}
+
+// runHarnessOnce executes a single harness attempt, delivering the prompt via
+// stdin, as an argument ({prompt} placeholder or appended), or as a temp file
+// ({prompt_file} placeholder or appended). Cancelling ctx forwards an interrupt
+// to the harness and kills it if it does not exit within harnessKillDelay.
+func runHarnessOnce(ctx context.Context, profile domain.HarnessProfile, prompt string, stdout io.Writer) error {
+	var args []string
+	var stdin io.Reader
+
+	switch profile.Prompt {
+	case domain.PromptViaArgv:
+		args = substituteArg(profile.Args, "{prompt}", prompt)
+	case domain.PromptViaFile:
+		tmp, err := os.CreateTemp("", "specfirst-prompt-*.md")
+		if err != nil {
+			return fmt.Errorf("creating prompt file: %w", err)
+		}
+		defer os.Remove(tmp.Name())
+		if _, err := tmp.WriteString(prompt); err != nil {
+			_ = tmp.Close()
+			return fmt.Errorf("writing prompt file: %w", err)
+		}
+		if err := tmp.Close(); err != nil {
+			return fmt.Errorf("writing prompt file: %w", err)
+		}
+		args = substituteArg(profile.Args, "{prompt_file}", tmp.Name())
+	default:
+		args = append(args, profile.Args...)
+		stdin = strings.NewReader(prompt)
+	}
+
+	runCtx := ctx
+	if profile.Timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, profile.Timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(runCtx, profile.Command, args...)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), profile.Env...)
+	cmd.Cancel = func() error {
+		// Interrupts are not supported on every platform; fall back to killing.
+		if err := cmd.Process.Signal(os.Interrupt); err != nil {
+			return cmd.Process.Kill()
+		}
+		return nil
+	}
+	cmd.WaitDelay = harnessKillDelay
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == nil && errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("harness %s timed out after %s: %w", profile.Command, profile.Timeout, context.DeadlineExceeded)
+		}
+		return err
+	}
+	return nil
+}
+
+// isRetryableHarnessError reports whether a failed attempt may succeed on retry:
+// non-zero exits and timeouts are retried, failures to start are not.
+func isRetryableHarnessError(err error) bool {
+	var exitErr *exec.ExitError
+	return errors.As(err, &exitErr) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// savePartialOutput stores the output of a failed harness attempt.
+func savePartialOutput(stageID string, attempt int, data []byte) (string, error) {
+	name := fmt.Sprintf("%s-%s-attempt%d.partial.md", stageID, time.Now().UTC().Format("20060102T150405Z"), attempt)
+	path := repository.GeneratedPath("harness", name)
+	if err := repository.WriteOutput(path, string(data)); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// substituteArg replaces placeholder in args with value, appending value when
+// no argument contains the placeholder.
+func substituteArg(args []string, placeholder, value string) []string {
+	out := make([]string, 0, len(args)+1)
+	replaced := false
+	for _, arg := range args {
+		if strings.Contains(arg, placeholder) {
+			arg = strings.ReplaceAll(arg, placeholder, value)
+			replaced = true
+		}
+		out = append(out, arg)
+	}
+	if !replaced {
+		out = append(out, value)
+	}
+	return out
+}
+
+func splitArgs(value string) ([]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	fields := []string{}
+	current := strings.Builder{}
+	inQuote := rune(0)
+	escaped := false
+	for _, r := range value {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case inQuote != 0:
+			if r == inQuote {
+				inQuote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			inQuote = r
+		case r == ' ' || r == '\t':
+			if current.Len() > 0 {
+				fields = append(fields, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if escaped {
+		return nil, fmt.Errorf("invalid args: unfinished escape")
+	}
+	if inQuote != 0 {
+		return nil, fmt.Errorf("invalid args: unterminated quote")
+	}
+	if current.Len() > 0 {
+		fields = append(fields, current.String())
+	}
+	return fields, nil
+}
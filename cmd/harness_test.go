@@ -1,8 +1,18 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"specfirst/internal/domain"
+	"specfirst/internal/repository"
 )
 
 func TestSplitArgs(t *testing.T) {
@@ -56,3 +66,163 @@ func TestSplitArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveHarness(t *testing.T) {
+	cfg := domain.Config{
+		Harness:     "claude",
+		HarnessArgs: "-p --verbose",
+		Harnesses: map[string]domain.HarnessProfile{
+			"review": {Command: "codex", Args: []string{"exec"}},
+			"broken": {Command: "x", Prompt: "pigeon"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		stage    domain.Stage
+		override string
+		want     string
+		wantArgs []string
+		wantErr  string
+	}{
+		{name: "legacy config harness", want: "claude", wantArgs: []string{"-p", "--verbose"}},
+		{name: "stage profile", stage: domain.Stage{Harness: "review"}, want: "codex", wantArgs: []string{"exec"}},
+		{name: "flag beats stage", stage: domain.Stage{Harness: "review"}, override: "claude", want: "claude", wantArgs: []string{"-p", "--verbose"}},
+		{name: "case insensitive", override: "Review", want: "codex", wantArgs: []string{"exec"}},
+		{name: "unknown profile", override: "nope", wantErr: "available: broken, review"},
+		{name: "invalid delivery", override: "broken", wantErr: "invalid prompt delivery"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := resolveHarness(cfg, tt.stage, tt.override)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil || !ok {
+				t.Fatalf("resolveHarness() = %v, %v", ok, err)
+			}
+			if got.Command != tt.want || !reflect.DeepEqual(got.Args, tt.wantArgs) {
+				t.Fatalf("resolveHarness() = %s %v, want %s %v", got.Command, got.Args, tt.want, tt.wantArgs)
+			}
+		})
+	}
+
+	if _, ok, err := resolveHarness(domain.Config{}, domain.Stage{}, ""); ok || err != nil {
+		t.Fatalf("expected no harness for empty config, got ok=%v err=%v", ok, err)
+	}
+}
+
+func TestRunHarnessPromptDelivery(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile domain.HarnessProfile
+		want    string
+	}{
+		{name: "stdin", profile: domain.HarnessProfile{Command: "cat"}, want: "hello"},
+		{name: "argv placeholder", profile: domain.HarnessProfile{Command: "printf", Args: []string{"[%s]", "{prompt}"}, Prompt: domain.PromptViaArgv}, want: "[hello]"},
+		{name: "file appended", profile: domain.HarnessProfile{Command: "cat", Prompt: domain.PromptViaFile}, want: "hello"},
+		{name: "env", profile: domain.HarnessProfile{Command: "sh", Args: []string{"-c", "printf %s \"$SPECFIRST_TEST_VAR\""}, Env: []string{"SPECFIRST_TEST_VAR=from-env"}}, want: "from-env"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			got, err := runHarness(context.Background(), "test", tt.profile, "hello", &out)
+			if err != nil {
+				t.Fatalf("runHarness() error: %v", err)
+			}
+			if got != tt.want || out.String() != tt.want {
+				t.Fatalf("runHarness() output = %q (streamed %q), want %q", got, out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRunHarnessTimeout(t *testing.T) {
+	profile := domain.HarnessProfile{Command: "sleep", Args: []string{"5"}, Timeout: 50 * time.Millisecond}
+	_, err := runHarness(context.Background(), "test", profile, "", io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+}
+
+func TestRunHarnessRetriesAndSavesPartialOutput(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	tmp := t.TempDir()
+	if err := os.Chdir(tmp); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	// Fails on the first attempt after printing partial output, succeeds on the second.
+	script := `if [ -f attempted ]; then printf done; else touch attempted; printf partial; exit 1; fi`
+	profile := domain.HarnessProfile{
+		Command:      "sh",
+		Args:         []string{"-c", script},
+		Retries:      2,
+		RetryBackoff: time.Millisecond,
+	}
+	got, err := runHarness(context.Background(), "draft", profile, "", nil)
+	if err != nil {
+		t.Fatalf("runHarness() error: %v", err)
+	}
+	if got != "done" {
+		t.Fatalf("runHarness() output = %q, want %q", got, "done")
+	}
+
+	partials, err := filepath.Glob(filepath.Join(repository.GeneratedPath("harness"), "draft-*-attempt1.partial.md"))
+	if err != nil || len(partials) != 1 {
+		t.Fatalf("expected one saved partial output, got %v (err=%v)", partials, err)
+	}
+	content, err := os.ReadFile(partials[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "partial" {
+		t.Fatalf("partial output = %q, want %q", content, "partial")
+	}
+}
+
+func TestRunHarnessDoesNotRetryCancelledRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	profile := domain.HarnessProfile{Command: "sleep", Args: []string{"5"}, Retries: 3, RetryBackoff: time.Millisecond}
+	_, err := runHarness(ctx, "test", profile, "", io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "interrupted") {
+		t.Fatalf("expected interrupted error, got %v", err)
+	}
+}
+
+func TestLoadConfigHarnessProfiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := `harness: spec
+harnesses:
+  spec:
+    command: claude
+    args: ["-p"]
+    env: ["MODEL=opus"]
+    timeout: 90s
+    prompt: stdin
+    retries: 2
+    retry_backoff: 5s
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := repository.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error: %v", err)
+	}
+	profile, ok := cfg.Harnesses["spec"]
+	if !ok {
+		t.Fatalf("expected spec profile, got %v", cfg.Harnesses)
+	}
+	if profile.Command != "claude" || profile.Timeout != 90*time.Second || profile.Retries != 2 || profile.RetryBackoff != 5*time.Second || !reflect.DeepEqual(profile.Env, []string{"MODEL=opus"}) {
+		t.Fatalf("unexpected profile: %+v", profile)
+	}
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"specfirst/internal/app"
+	"specfirst/internal/repository"
+)
+
+func TestRepeatableStageRecordsIterations(t *testing.T) {
+	root := setupCaptureWorkspace(t, `name: capture
+version: "1"
+stages:
+  - id: review
+    name: Review
+    template: review.md
+    outputs: [review.md]
+    repeatable: true
+`, map[string]string{"review.md": "{{ range .Iterations }}[{{ .Number }}:{{ (index .Artifacts 0).Content }}]{{ end }}"})
+	stageCapture = false
+
+	complete := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(root, "review.md"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		application, err := app.Load("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := application.CompleteStage(context.Background(), "review", []string{"review.md"}, false, ""); err != nil {
+			t.Fatalf("CompleteStage: %v", err)
+		}
+	}
+	complete("first draft")
+	complete("second draft")
+
+	application, err := app.Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	iterations := application.State.Iterations["review"]
+	if len(iterations) != 2 || iterations[1].Iteration != 2 {
+		t.Fatalf("expected 2 iterations, got %+v", iterations)
+	}
+	if got := application.State.StageOutputs["review"].Iteration; got != 2 {
+		t.Fatalf("latest output iteration = %d, want 2", got)
+	}
+	first, err := os.ReadFile(repository.HistoryPath("review", "1", "review.md"))
+	if err != nil || string(first) != "first draft" {
+		t.Fatalf("expected first iteration to be kept, got %q (%v)", first, err)
+	}
+
+	stage, _ := application.Protocol.StageByID("review")
+	prompt, err := application.CompilePrompt(stage, []string{"review"}, app.CompileOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prompt != "[1:first draft][2:second draft]" {
+		t.Fatalf("unexpected iteration history in template: %q", prompt)
+	}
+
+	diffStage = "review"
+	t.Cleanup(func() { diffStage, diffIteration = "", 0 })
+	var out bytes.Buffer
+	diffCmd.SetOut(&out)
+	t.Cleanup(func() { diffCmd.SetOut(nil) })
+	if err := diffCmd.RunE(diffCmd, nil); err != nil {
+		t.Fatalf("diff --stage: %v", err)
+	}
+	if got := out.String(); !strings.Contains(got, "first draft") || !strings.Contains(got, "second draft") {
+		t.Fatalf("expected diff prompt to include both iterations, got:\n%s", got)
+	}
+
+	diffIteration = 1
+	if err := diffCmd.RunE(diffCmd, nil); err == nil || !strings.Contains(err.Error(), "no previous iteration") {
+		t.Fatalf("expected error comparing the first iteration, got %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+
+	"specfirst/internal/app"
+
+	"github.com/spf13/cobra"
+)
+
+var ledgerCmd = &cobra.Command{
+	Use:   "ledger",
+	Short: "Manage the epistemic ledger as a whole",
+}
+
+var ledgerImportCmd = &cobra.Command{
+	Use:   "import <stage>",
+	Short: "Import assumptions, questions, risks and decisions from a stage's artifacts",
+	Long: `Import ledger entries from the stored artifacts of a completed stage.
+
+List items under headings such as "Assumptions", "Open Questions", "Risks" and
+"Decisions" are imported, as are the items of fenced YAML blocks with
+assumptions, open_questions, risks or decisions keys. Entries that match an
+existing entry are skipped. Each imported entry records its source artifact
+and line.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: stageIDCompletions,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		application, err := app.Load(protocolFlag)
+		if err != nil {
+			return err
+		}
+		imported, err := application.ImportLedger(args[0], ledgerUser())
+		if err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		added, duplicates := 0, 0
+		for _, entry := range imported {
+			location := fmt.Sprintf("%s:%d", entry.Artifact, entry.Line)
+			if entry.Duplicate {
+				duplicates++
+				fmt.Fprintf(out, "  = %-10s %s (%s, already recorded)\n", entry.Kind, entry.Text, location)
+				continue
+			}
+			added++
+			fmt.Fprintf(out, "  + %-10s [%s] %s (%s)\n", entry.Kind, entry.ID, entry.Text, location)
+		}
+		fmt.Fprintf(out, "Imported %d ledger entries from stage %s (%d duplicate(s) skipped)\n", added, args[0], duplicates)
+		return nil
+	},
+}
+
+func init() {
+	ledgerImportCmd.Flags().StringVar(&epistemicBy, "by", "", "who is importing the entries (defaults to $USER)")
+	ledgerCmd.AddCommand(ledgerImportCmd)
+}
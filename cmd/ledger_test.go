@@ -0,0 +1,394 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"specfirst/internal/app"
+	"specfirst/internal/domain"
+	"specfirst/internal/repository"
+
+	"github.com/spf13/cobra"
+)
+
+func TestLedgerImportLinksSourcesAndDedupes(t *testing.T) {
+	root := setupCaptureWorkspace(t, `name: capture
+version: "1"
+stages:
+  - id: requirements
+    name: Requirements
+    template: requirements.md
+    outputs: [requirements.md]
+`, map[string]string{"requirements.md": "# Context\n"})
+	stageCapture = false
+	t.Cleanup(func() { ledgerImportCmd.SetOut(nil) })
+
+	artifact := "# Requirements\n\n## Assumptions\n- Users sign in with SSO\n\n## Open Questions\n- Which region hosts the data?\n\n## Risks\n- [high] Vendor rate limits\n"
+	if err := os.WriteFile(filepath.Join(root, "requirements.md"), []byte(artifact), 0644); err != nil {
+		t.Fatal(err)
+	}
+	application, err := app.Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	application.State.AddAssumption("users sign in with SSO.", "", domain.Provenance{})
+	if err := application.CompleteStage(context.Background(), "requirements", []string{"requirements.md"}, false, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	ledgerImportCmd.SetOut(&out)
+	if err := ledgerImportCmd.RunE(ledgerImportCmd, []string{"requirements"}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Imported 2 ledger entries from stage requirements (1 duplicate(s) skipped)") {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := ledgerImportCmd.RunE(ledgerImportCmd, []string{"requirements"}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Imported 0 ledger entries from stage requirements (3 duplicate(s) skipped)") {
+		t.Fatalf("expected a second import to add nothing:\n%s", out.String())
+	}
+
+	s, err := repository.LoadState(repository.StatePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := s.Epistemics
+	if len(e.Assumptions) != 1 || len(e.OpenQuestions) != 1 || len(e.Risks) != 1 {
+		t.Fatalf("unexpected ledger: %+v", e)
+	}
+	risk := e.Risks[0]
+	if risk.Text != "Vendor rate limits" || risk.Severity != "high" || risk.SourceArtifact != ".specfirst/artifacts/requirements/requirements.md" || risk.SourceLine != 10 {
+		t.Fatalf("unexpected risk: %+v", risk)
+	}
+	if q := e.OpenQuestions[0]; q.SourceLine != 7 || q.Status != "open" {
+		t.Fatalf("unexpected question: %+v", q)
+	}
+}
+
+func TestLedgerGatesScopedToUpstreamStages(t *testing.T) {
+	root := setupCaptureWorkspace(t, `name: capture
+version: "1"
+stages:
+  - id: design
+    name: Design
+    template: design.md
+    outputs: [design.md]
+  - id: ops
+    name: Ops
+    template: ops.md
+    outputs: [ops.md]
+  - id: build
+    name: Build
+    template: build.md
+    depends_on: [design]
+    outputs: [build.md]
+    max_open_questions: 0
+    must_resolve_tags: [security]
+`, map[string]string{"design.md": "# Design\n", "ops.md": "# Ops\n", "build.md": "# Build\n"})
+	stageCapture = false
+
+	for _, name := range []string{"design.md", "build.md"} {
+		if err := os.WriteFile(filepath.Join(root, name), []byte("# "+name+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	application, err := app.Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := application.CompleteStage(context.Background(), "design", []string{"design.md"}, false, ""); err != nil {
+		t.Fatal(err)
+	}
+	// Questions raised by an unrelated stage do not gate build.
+	application.State.AddOpenQuestion("Which pager rotation?", []string{"security"}, "", domain.Provenance{Stage: "ops", CreatedBy: "sam"})
+	application.State.AddOpenQuestion("Which cache?", nil, "", domain.Provenance{Stage: "build", CreatedBy: "sam"})
+	q := application.State.Epistemics.OpenQuestions[1]
+	if len(q.History) != 1 || q.History[0].Action != "created" || q.History[0].By != "sam" {
+		t.Fatalf("unexpected history: %+v", q.History)
+	}
+	if err := application.ValidateAmbiguityGates(mustStage(t, application, "build")); err == nil || !strings.Contains(err.Error(), "1 open questions") {
+		t.Fatalf("expected the build question to count, got %v", err)
+	}
+	application.State.ResolveOpenQuestion(q.ID, "redis", "sam")
+	if err := application.ValidateAmbiguityGates(mustStage(t, application, "build")); err != nil {
+		t.Fatalf("expected the ops question to be out of scope, got %v", err)
+	}
+	application.State.AddOpenQuestion("Who holds the keys?", []string{"security"}, "", domain.Provenance{Stage: "design"})
+	if err := application.ValidateAmbiguityGates(mustStage(t, application, "build")); err == nil {
+		t.Fatal("expected an upstream question to fail the gate")
+	}
+	if err := application.SaveState(); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	statusCmd.SetOut(&out)
+	t.Cleanup(func() { statusCmd.SetOut(nil) })
+	if err := statusCmd.RunE(statusCmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	want := "Ledger by stage:\n" +
+		"  - design: 0 assumption(s) (0 open), 1 question(s) (1 open), 0 risk(s) (0 open), 0 decision(s) (0 proposed)\n" +
+		"  - ops: 0 assumption(s) (0 open), 1 question(s) (1 open), 0 risk(s) (0 open), 0 decision(s) (0 proposed)\n" +
+		"  - build: 0 assumption(s) (0 open), 1 question(s) (0 open), 0 risk(s) (0 open), 0 decision(s) (0 proposed)\n"
+	if !strings.Contains(out.String(), want) {
+		t.Fatalf("unexpected status output:\n%s", out.String())
+	}
+}
+
+func mustStage(t *testing.T, application *app.Application, id string) domain.Stage {
+	t.Helper()
+	stage, ok := application.Protocol.StageByID(id)
+	if !ok {
+		t.Fatalf("unknown stage %s", id)
+	}
+	return stage
+}
+
+func TestLedgerLifecycleCommands(t *testing.T) {
+	setupCaptureWorkspace(t, `name: capture
+version: "1"
+stages:
+  - id: design
+    name: Design
+    template: design.md
+`, map[string]string{"design.md": "# Design\n"})
+	t.Cleanup(func() { epistemicBy, epistemicOwner, epistemicStage = "", "", "" })
+	epistemicBy = "sam"
+
+	run := func(c *cobra.Command, args ...string) error {
+		t.Helper()
+		return c.RunE(c, args)
+	}
+	sub := func(parent *cobra.Command, name string) *cobra.Command {
+		t.Helper()
+		for _, c := range parent.Commands() {
+			if c.Name() == name {
+				return c
+			}
+		}
+		t.Fatalf("%s has no %s command", parent.Name(), name)
+		return nil
+	}
+	load := func() domain.State {
+		t.Helper()
+		s, err := repository.LoadState(repository.StatePath())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	if err := run(questionAddCmd, "Which region?"); err != nil {
+		t.Fatal(err)
+	}
+	if err := run(riskAddCmd, "Vendor lock-in", "severe"); err == nil || !strings.Contains(err.Error(), `invalid severity "severe"`) {
+		t.Fatalf("expected severity validation, got %v", err)
+	}
+	if err := run(disputeAddCmd, "Queue or cron?"); err != nil {
+		t.Fatal(err)
+	}
+	s := load()
+	qid, did := s.Epistemics.OpenQuestions[0].ID, s.Epistemics.Disputes[0].ID
+
+	if err := run(sub(questionCmd, "edit"), qid, "Which EU region?"); err != nil {
+		t.Fatal(err)
+	}
+	if err := questionDeferCmd.Flags().Set("reason", "after launch"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = questionDeferCmd.Flags().Set("reason", "") })
+	if err := run(questionDeferCmd, qid); err != nil {
+		t.Fatal(err)
+	}
+	q := load().Epistemics.OpenQuestions[0]
+	if q.Text != "Which EU region?" || q.Status != "deferred" {
+		t.Fatalf("unexpected question: %+v", q)
+	}
+	if err := run(sub(questionCmd, "reopen"), qid); err != nil {
+		t.Fatal(err)
+	}
+	q = load().Epistemics.OpenQuestions[0]
+	var actions []string
+	for _, h := range q.History {
+		actions = append(actions, h.Action+":"+h.Status)
+	}
+	if q.Status != "open" || strings.Join(actions, ",") != "created:open,edited:open,deferred:deferred,reopened:open" {
+		t.Fatalf("unexpected question after reopen: %+v", q)
+	}
+	if err := run(sub(questionCmd, "rm"), qid); err != nil {
+		t.Fatal(err)
+	}
+	if err := run(sub(questionCmd, "rm"), qid); err == nil || err.Error() != "question "+qid+" not found" {
+		t.Fatalf("expected a missing question, got %v", err)
+	}
+
+	epistemicOwner = "ana"
+	if err := run(disputePositionAddCmd, did, "Use a queue"); err != nil {
+		t.Fatal(err)
+	}
+	if err := disputeResolveCmd.Flags().Set("outcome", "queue"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = disputeResolveCmd.Flags().Set("outcome", "") })
+	if err := run(disputeResolveCmd, did); err != nil {
+		t.Fatal(err)
+	}
+	d := load().Epistemics.Disputes[0]
+	if d.Status != "resolved" || d.Outcome != "queue" || len(d.Positions) != 1 || d.Positions[0].Owner != "ana" {
+		t.Fatalf("unexpected dispute: %+v", d)
+	}
+
+	if err := run(confidenceSetCmd, "certain"); err == nil {
+		t.Fatal("expected an invalid confidence level to fail")
+	}
+	epistemicStage = "design"
+	if err := run(confidenceSetCmd, "high"); err != nil {
+		t.Fatal(err)
+	}
+	epistemicStage = "deploy"
+	if err := run(confidenceSetCmd, "low"); err == nil || err.Error() != "unknown stage: deploy" {
+		t.Fatalf("expected an unknown stage, got %v", err)
+	}
+	if c := load().Epistemics.Confidence; c.ByStage["design"] != "high" || c.Overall != "" {
+		t.Fatalf("unexpected confidence: %+v", c)
+	}
+
+	if err := run(assumeAddCmd, "Traffic is bursty"); err == nil || err.Error() != "unknown stage: deploy" {
+		t.Fatalf("expected an unknown stage, got %v", err)
+	}
+	epistemicStage = "design"
+	if err := run(assumeAddCmd, "Traffic is bursty"); err != nil {
+		t.Fatal(err)
+	}
+	if a := load().Epistemics.Assumptions; len(a) != 1 || a[0].Stage != "design" {
+		t.Fatalf("unexpected assumptions: %+v", a)
+	}
+
+	if err := decisionUpdateCmd.Flags().Set("status", "superseded"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = decisionUpdateCmd.Flags().Set("status", "accepted") })
+	if err := run(decisionUpdateCmd, "abc"); err == nil || !strings.Contains(err.Error(), "use proposed, accepted, reversed") {
+		t.Fatalf("expected status validation, got %v", err)
+	}
+}
+
+func TestDecisionADRExportAndImport(t *testing.T) {
+	root := setupCaptureWorkspace(t, `name: capture
+version: "1"
+stages:
+  - id: design
+    name: Design
+    template: design.md
+`, map[string]string{"design.md": "# Design\n"})
+	t.Cleanup(func() {
+		decisionExportCmd.SetOut(nil)
+		decisionImportCmd.SetOut(nil)
+	})
+
+	adrDir := filepath.Join(root, "docs", "adr")
+	if err := os.MkdirAll(adrDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	existing := "# 1. Record architecture decisions\n\n## Status\n\nAccepted\n\n## Decision\n\nWe will keep ADRs.\n"
+	if err := os.WriteFile(filepath.Join(adrDir, "0001-record-architecture-decisions.md"), []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	application, err := app.Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &application.State
+	s.AddDecision("Use Postgres", "it supports JSON columns.", []string{"MySQL"}, domain.Provenance{})
+	reversed := s.AddDecision("Queue writes", "", nil, domain.Provenance{})
+	s.UpdateDecision(reversed, "reversed", "sam")
+	proposed := s.AddDecision("Shard by tenant", "", nil, domain.Provenance{})
+	s.UpdateDecision(proposed, "proposed", "sam")
+	if err := application.SaveState(); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	decisionExportCmd.SetOut(&out)
+	if err := decisionExportCmd.RunE(decisionExportCmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "wrote docs/adr/0002-use-postgres.md") || !strings.Contains(out.String(), "Exported 2 decision(s) to docs/adr") {
+		t.Fatalf("unexpected export output:\n%s", out.String())
+	}
+	data, err := os.ReadFile(filepath.Join(adrDir, "0003-queue-writes.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "status: superseded\n") {
+		t.Fatalf("expected the reversed decision to be superseded:\n%s", data)
+	}
+
+	// Exporting again rewrites the same files.
+	out.Reset()
+	if err := decisionExportCmd.RunE(decisionExportCmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out.String(), "0004") {
+		t.Fatalf("expected stable numbering:\n%s", out.String())
+	}
+
+	out.Reset()
+	decisionImportCmd.SetOut(&out)
+	if err := decisionImportCmd.RunE(decisionImportCmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Imported 1 decision(s) from docs/adr (2 duplicate(s) skipped)") {
+		t.Fatalf("unexpected import output:\n%s", out.String())
+	}
+	state, err := repository.LoadState(repository.StatePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := state.Epistemics.Decisions[3]
+	if d.Text != "Record architecture decisions" || d.Status != "accepted" || d.Rationale != "We will keep ADRs." || d.ADR != "0001-record-architecture-decisions.md" {
+		t.Fatalf("unexpected imported decision: %+v", d)
+	}
+	// An imported record is left alone until its decision is reversed.
+	imported := filepath.Join(adrDir, "0001-record-architecture-decisions.md")
+	out.Reset()
+	if err := decisionExportCmd.RunE(decisionExportCmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out.String(), "0001") {
+		t.Fatalf("expected the imported record to be left as written:\n%s", out.String())
+	}
+	if data, err := os.ReadFile(imported); err != nil || string(data) != existing {
+		t.Fatalf("expected the imported record to be unchanged, got %q (%v)", data, err)
+	}
+
+	state.UpdateDecision(d.ID, "reversed", "sam")
+	if err := repository.SaveState(repository.StatePath(), state); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := decisionExportCmd.RunE(decisionExportCmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "wrote docs/adr/0001-record-architecture-decisions.md") {
+		t.Fatalf("expected the reversed record to be re-rendered:\n%s", out.String())
+	}
+	data, err = os.ReadFile(imported)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "status: superseded\n") {
+		t.Fatalf("expected the reversed record to be superseded:\n%s", data)
+	}
+}
@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 
 	"specfirst/internal/app"
@@ -9,14 +11,50 @@ import (
 var lintCmd = &cobra.Command{
 	Use:   "lint",
 	Short: "Run non-blocking checks on the workspace",
+	Long: `Run non-blocking checks on the workspace.
+
+With --artifacts, lint the stored artifacts of completed stages instead: each
+is checked against its output contract, the forbidden phrases and ambiguity
+detector used for prompts, and artifact-scoped lint rules. Findings point at
+artifact lines; --to-questions records them as open questions.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		artifacts, _ := cmd.Flags().GetBool("artifacts")
+		stageID, _ := cmd.Flags().GetString("stage")
+		toQuestions, _ := cmd.Flags().GetBool("to-questions")
+		if !artifacts && (stageID != "" || toQuestions) {
+			return fmt.Errorf("--stage and --to-questions require --artifacts")
+		}
+
 		application, err := app.Load(protocolFlag)
 		if err != nil {
 			return err
 		}
 
-		// Delegate to App.Check which handles all validation
-		// failOnWarnings=false means return nil even if there are warnings
-		return application.Check(false)
+		if !artifacts {
+			// Delegate to App.Check which handles all validation; lint never fails on findings
+			return writeFindings(cmd.OutOrStdout(), stageFormat, application, application.Check())
+		}
+
+		findings, err := application.LintArtifacts(stageID)
+		if err != nil {
+			return err
+		}
+		if err := writeFindings(cmd.OutOrStdout(), stageFormat, application, findings); err != nil {
+			return err
+		}
+		if toQuestions {
+			added, err := application.AddLintQuestions(findings, ledgerUser())
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "Added %d open question(s) from %d finding(s)\n", added, len(findings))
+		}
+		return nil
 	},
 }
+
+func init() {
+	lintCmd.Flags().Bool("artifacts", false, "lint stored artifacts instead of prompts")
+	lintCmd.Flags().String("stage", "", "only lint the artifacts of this stage (with --artifacts)")
+	lintCmd.Flags().Bool("to-questions", false, "record artifact findings as open questions (with --artifacts)")
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"specfirst/internal/app"
+	"specfirst/internal/repository"
+)
+
+func TestCheckCustomLintRules(t *testing.T) {
+	root := setupCaptureWorkspace(t, `name: capture
+version: "1"
+stages:
+  - id: design
+    name: Design
+    template: design.md
+    outputs: [design.md]
+  - id: plan
+    name: Plan
+    template: plan.md
+    depends_on: [design]
+`, map[string]string{
+		"design.md": "# Context\n# Task\nUse the legacy API.\n# Assumptions\n",
+		"plan.md":   "# Context\n# Task\nUse the legacy API.\n# Assumptions\n",
+	})
+	stageCapture = false
+	t.Cleanup(func() {
+		stageFormat = "text"
+		checkCmd.SetOut(nil)
+	})
+	rules := `rules:
+  - id: no-legacy-api
+    pattern: '(?i)legacy api'
+    message: use the v2 API instead
+    stages: [plan]
+  - id: no-tbd
+    pattern: '\bTBD\b'
+    message: resolve placeholders before handing off
+    severity: error
+    scope: artifact
+`
+	if err := os.WriteFile(repository.LintRulesPath(), []byte(rules), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "design.md"), []byte("# Design\n\nOwner: TBD\nDeadline: TBD\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	application, err := app.Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := application.CompleteStage(context.Background(), "design", []string{"design.md"}, false, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	checkCmd.SetOut(&out)
+	stageFormat = "json"
+	if err := checkCmd.RunE(checkCmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	var report struct {
+		Findings []app.Finding `json:"findings"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+
+	var legacy []string
+	var tbdLines []int
+	for _, f := range report.Findings {
+		switch f.RuleID {
+		case "no-legacy-api":
+			legacy = append(legacy, f.Stage)
+			if f.Severity != app.SeverityWarning {
+				t.Fatalf("expected default warning severity, got %+v", f)
+			}
+		case "no-tbd":
+			tbdLines = append(tbdLines, f.Line)
+			if f.Severity != app.SeverityError || f.File != ".specfirst/artifacts/design/design.md" {
+				t.Fatalf("unexpected artifact finding: %+v", f)
+			}
+		}
+	}
+	if len(legacy) != 1 || legacy[0] != "plan" {
+		t.Fatalf("expected the prompt rule to apply to plan only, got %v in %+v", legacy, report.Findings)
+	}
+	if len(tbdLines) != 2 || tbdLines[0] != 3 || tbdLines[1] != 4 {
+		t.Fatalf("expected artifact matches on lines 3 and 4, got %v", tbdLines)
+	}
+}
+
+func TestLintArtifactsToQuestions(t *testing.T) {
+	root := setupCaptureWorkspace(t, `name: capture
+version: "1"
+stages:
+  - id: requirements
+    name: Requirements
+    template: requirements.md
+    outputs: [requirements.md]
+`, map[string]string{"requirements.md": "# Context\n# Task\n# Assumptions\n"})
+	stageCapture = false
+	t.Cleanup(func() {
+		stageFormat = "text"
+		lintCmd.SetOut(nil)
+		lintCmd.SetErr(nil)
+		for _, name := range []string{"artifacts", "stage", "to-questions"} {
+			_ = lintCmd.Flags().Set(name, lintCmd.Flags().Lookup(name).DefValue)
+		}
+	})
+	artifact := "# Requirements\n\nRetry as appropriate.\nSupport CSV, JSON, etc.\n"
+	if err := os.WriteFile(filepath.Join(root, "requirements.md"), []byte(artifact), 0644); err != nil {
+		t.Fatal(err)
+	}
+	application, err := app.Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := application.CompleteStage(context.Background(), "requirements", []string{"requirements.md"}, false, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, flag := range []string{"artifacts", "to-questions"} {
+		if err := lintCmd.Flags().Set(flag, "true"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run := func() {
+		t.Helper()
+		var out, errOut bytes.Buffer
+		lintCmd.SetOut(&out)
+		lintCmd.SetErr(&errOut)
+		if err := lintCmd.RunE(lintCmd, nil); err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range []string{
+			`requirements.md:3:7: vague language detected: "as appropriate" [prompt-ambiguity]`,
+			`requirements.md:4:20: vague language detected: "etc" [prompt-ambiguity]`,
+		} {
+			if !bytes.Contains(out.Bytes(), []byte(want)) {
+				t.Fatalf("expected %q in output:\n%s", want, out.String())
+			}
+		}
+	}
+	run()
+	run() // questions are not added twice
+
+	s, err := repository.LoadState(repository.StatePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	questions := s.Epistemics.OpenQuestions
+	if len(questions) != 2 {
+		t.Fatalf("expected 2 open questions, got %+v", questions)
+	}
+	if questions[0].Context != ".specfirst/artifacts/requirements/requirements.md:3" || questions[0].Status != "open" {
+		t.Fatalf("unexpected question: %+v", questions[0])
+	}
+}
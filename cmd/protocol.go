@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 
 	"specfirst/internal/assets"
 	"specfirst/internal/repository"
@@ -49,6 +51,8 @@ var protocolListCmd = &cobra.Command{
 	},
 }
 
+var protocolShowResolved bool
+
 var protocolShowCmd = &cobra.Command{
 	Use:               "show <name>",
 	Short:             "Show a protocol definition",
@@ -68,6 +72,16 @@ var protocolShowCmd = &cobra.Command{
 			}
 			return err
 		}
+		if protocolShowResolved {
+			proto, err := repository.LoadProtocol(path)
+			if err != nil {
+				return err
+			}
+			data, err = yaml.Marshal(proto)
+			if err != nil {
+				return err
+			}
+		}
 		fmt.Fprintln(cmd.OutOrStdout(), string(data))
 		return nil
 	},
@@ -104,8 +118,76 @@ var protocolCreateCmd = &cobra.Command{
 	},
 }
 
+var protocolValidateCmd = &cobra.Command{
+	Use:   "validate <file|name>",
+	Short: "Validate a protocol and report every error and warning",
+	Long: `Validate a protocol file (or a protocol name in .specfirst/protocols) and its
+imports. Every problem is reported with its file:line:column. Use --format json
+for CI and editor integrations. Exits non-zero when errors are found.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: protocolNameCompletions,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := args[0]
+		if _, err := os.Stat(path); os.IsNotExist(err) && validProtocolNamePattern.MatchString(path) {
+			path = repository.ProtocolsPath(path + ".yaml")
+		}
+
+		_, diags, err := repository.CheckProtocol(path)
+		if err != nil {
+			return err
+		}
+
+		errorCount, warningCount := 0, 0
+		for _, d := range diags {
+			if d.Severity == repository.SeverityError {
+				errorCount++
+			} else {
+				warningCount++
+			}
+		}
+
+		switch stageFormat {
+		case "json":
+			report := struct {
+				File        string                  `json:"file"`
+				Valid       bool                    `json:"valid"`
+				Errors      int                     `json:"errors"`
+				Warnings    int                     `json:"warnings"`
+				Diagnostics []repository.Diagnostic `json:"diagnostics"`
+			}{path, errorCount == 0, errorCount, warningCount, diags}
+			if report.Diagnostics == nil {
+				report.Diagnostics = []repository.Diagnostic{}
+			}
+			data, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		case "text":
+			for _, d := range diags {
+				fmt.Fprintln(cmd.OutOrStdout(), d.String())
+			}
+			if len(diags) == 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s: protocol is valid\n", path)
+			} else {
+				fmt.Fprintf(cmd.OutOrStdout(), "%d error(s), %d warning(s)\n", errorCount, warningCount)
+			}
+		default:
+			return fmt.Errorf("unsupported format %q for protocol validate (use text or json)", stageFormat)
+		}
+
+		if errorCount > 0 {
+			return fmt.Errorf("protocol %s has %d error(s)", path, errorCount)
+		}
+		return nil
+	},
+}
+
 func init() {
 	protocolCmd.AddCommand(protocolListCmd)
 	protocolCmd.AddCommand(protocolShowCmd)
 	protocolCmd.AddCommand(protocolCreateCmd)
+	protocolCmd.AddCommand(protocolValidateCmd)
+
+	protocolShowCmd.Flags().BoolVar(&protocolShowResolved, "resolved", false, "print the protocol with uses, extends, overrides and remove applied")
 }
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"specfirst/internal/domain"
+	"specfirst/internal/repository"
+)
+
+const extendsBaseProtocol = `name: base
+version: "1"
+stages:
+  - id: requirements
+    name: Requirements
+    template: requirements.md
+    outputs: [requirements.md]
+  - id: design
+    name: Design
+    template: design.md
+    depends_on: [requirements]
+    inputs: [requirements.md]
+    outputs: [design.md]
+    prompt:
+      granularity: story
+      rules: [Keep it short]
+  - id: implement
+    name: Implement
+    template: implement.md
+    depends_on: [design]
+    inputs: [design.md]
+approvals:
+  - stage: design
+    role: architect
+  - stage: implement
+    role: lead
+`
+
+func setupExtendsWorkspace(t *testing.T, protocols map[string]string) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	tmp := t.TempDir()
+	if err := os.Chdir(tmp); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = os.Chdir(wd)
+		protocolShowResolved = false
+	})
+	if err := os.MkdirAll(repository.ProtocolsPath(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range protocols {
+		if err := os.WriteFile(repository.ProtocolsPath(name+".yaml"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestProtocolShowResolvedExtends(t *testing.T) {
+	setupExtendsWorkspace(t, map[string]string{
+		"base": extendsBaseProtocol,
+		"strict": `name: strict
+version: "2"
+extends: base
+remove: [implement]
+overrides:
+  - id: design
+    template: design-strict.md
+    max_open_questions: 0
+    prompt:
+      granularity: ticket
+stages:
+  - id: security
+    name: Security Review
+    template: security.md
+    after: requirements
+    depends_on: [requirements]
+    inputs: [requirements.md]
+    outputs: [threats.md]
+`,
+	})
+
+	protocolShowResolved = true
+	var out bytes.Buffer
+	protocolShowCmd.SetOut(&out)
+	t.Cleanup(func() { protocolShowCmd.SetOut(nil) })
+	if err := protocolShowCmd.RunE(protocolShowCmd, []string{"strict"}); err != nil {
+		t.Fatalf("protocol show --resolved: %v", err)
+	}
+
+	var proto domain.Protocol
+	if err := yaml.Unmarshal(out.Bytes(), &proto); err != nil {
+		t.Fatalf("resolved output is not a protocol: %v\n%s", err, out.String())
+	}
+	if proto.Extends != "" || len(proto.Overrides) != 0 || len(proto.Remove) != 0 {
+		t.Fatalf("resolved protocol should not keep inheritance keys:\n%s", out.String())
+	}
+
+	ids := []string{}
+	for _, stage := range proto.Stages {
+		ids = append(ids, stage.ID)
+	}
+	if want := []string{"requirements", "security", "design"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("stages = %v, want %v", ids, want)
+	}
+
+	design := proto.Stages[2]
+	if design.Template != "design-strict.md" || design.Name != "Design" {
+		t.Fatalf("override should patch template and keep name, got %+v", design)
+	}
+	if design.MaxOpenQuestions == nil || *design.MaxOpenQuestions != 0 {
+		t.Fatalf("override should add max_open_questions, got %v", design.MaxOpenQuestions)
+	}
+	if design.Prompt == nil || design.Prompt.Granularity != "ticket" || !reflect.DeepEqual(design.Prompt.Rules, []string{"Keep it short"}) {
+		t.Fatalf("prompt should be merged field by field, got %+v", design.Prompt)
+	}
+	if len(proto.Approvals) != 1 || proto.Approvals[0].Stage != "design" {
+		t.Fatalf("approvals for removed stages should be dropped, got %+v", proto.Approvals)
+	}
+}
+
+func TestProtocolExtendsReportsUnknownTargets(t *testing.T) {
+	setupExtendsWorkspace(t, map[string]string{
+		"base": extendsBaseProtocol,
+		"broken": `name: broken
+version: "1"
+extends: base
+remove: [deploy]
+overrides:
+  - id: review
+    template: review.md
+stages:
+  - id: notes
+    name: Notes
+    template: notes.md
+    before: planning
+`,
+	})
+
+	_, err := repository.LoadProtocol(repository.ProtocolsPath("broken.yaml"))
+	if err == nil {
+		t.Fatal("expected errors for unknown remove, override and placement targets")
+	}
+	for _, want := range []string{
+		`broken.yaml:4:10: error: cannot remove "deploy"`,
+		`broken.yaml:6:9: error: override for unknown stage "review"`,
+		`broken.yaml:12:13: error: stage "notes" is placed before unknown stage "planning"`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in error:\n%v", want, err)
+		}
+	}
+}
+
+func TestProtocolExtendsThreeLevelsKeepsInheritedFields(t *testing.T) {
+	setupExtendsWorkspace(t, map[string]string{
+		"base": extendsBaseProtocol,
+		"mid": `name: mid
+version: "1"
+extends: base
+overrides:
+  - id: design
+    template: design-mid.md
+`,
+		"top": `name: top
+version: "1"
+extends: mid
+overrides:
+  - id: design
+    name: Detailed Design
+    prompt:
+      granularity: ticket
+`,
+	})
+
+	proto, err := repository.LoadProtocol(repository.ProtocolsPath("top.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	design, ok := proto.StageByID("design")
+	if !ok {
+		t.Fatal("design stage missing")
+	}
+	if design.Name != "Detailed Design" || design.Template != "design-mid.md" {
+		t.Fatalf("expected both overrides to apply, got %+v", design)
+	}
+	if !reflect.DeepEqual(design.DependsOn, []string{"requirements"}) || !reflect.DeepEqual(design.Inputs, []string{"requirements.md"}) || !reflect.DeepEqual(design.Outputs, []string{"design.md"}) {
+		t.Fatalf("expected inherited depends_on, inputs and outputs, got %+v", design)
+	}
+	if design.Prompt == nil || design.Prompt.Granularity != "ticket" || !reflect.DeepEqual(design.Prompt.Rules, []string{"Keep it short"}) {
+		t.Fatalf("expected the base prompt rules to survive, got %+v", design.Prompt)
+	}
+}
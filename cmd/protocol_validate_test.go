@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"specfirst/internal/repository"
+)
+
+func TestProtocolValidateReportsAllDiagnostics(t *testing.T) {
+	dir := t.TempDir()
+	base := `name: base
+version: "1"
+stages:
+  - id: requirements
+    name: Requirements
+    template: requirements.md
+    outputs: [requirements.md]
+`
+	protocol := `name: broken
+version: "1"
+uses: [base]
+stages:
+  - id: design
+    name: Design
+    template: design.md
+    depends_on: [requirements, missing]
+    inputs: [notes.md]
+    outputs: [design.md]
+    optional: true
+  - id: ship
+    name: Ship
+    template: ship.md
+    depends_on: [design]
+    terminal: true
+  - id: after
+    name: After
+    template: after.md
+    depends_on: [ship]
+approvals:
+  - stage: design
+    role: architect
+`
+	if err := os.WriteFile(filepath.Join(dir, "base.yaml"), []byte(base), 0644); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "broken.yaml")
+	if err := os.WriteFile(path, []byte(protocol), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stageFormat = "json"
+	t.Cleanup(func() { stageFormat = "text" })
+
+	var out bytes.Buffer
+	protocolValidateCmd.SetOut(&out)
+	t.Cleanup(func() { protocolValidateCmd.SetOut(nil) })
+	err := protocolValidateCmd.RunE(protocolValidateCmd, []string{path})
+	if err == nil || !strings.Contains(err.Error(), "2 error(s)") {
+		t.Fatalf("expected 2 errors, got %v", err)
+	}
+
+	var report struct {
+		Valid       bool                    `json:"valid"`
+		Diagnostics []repository.Diagnostic `json:"diagnostics"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("invalid json output: %v\n%s", err, out.String())
+	}
+	if report.Valid {
+		t.Fatalf("expected report to be invalid")
+	}
+
+	want := []struct {
+		severity string
+		line     int
+		message  string
+	}{
+		{repository.SeverityError, 8, `depends on unknown stage "missing"`},
+		{repository.SeverityError, 9, `input "notes.md" not found`},
+		{repository.SeverityWarning, 17, `"after" is unreachable`},
+		{repository.SeverityWarning, 7, `output "requirements.md" of stage "requirements" is not consumed`},
+		{repository.SeverityWarning, 22, `requires optional stage "design"`},
+	}
+	for _, w := range want {
+		found := false
+		for _, d := range report.Diagnostics {
+			if d.Severity == w.severity && d.Line == w.line && strings.Contains(d.Message, w.message) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("missing %s at line %d containing %q in %+v", w.severity, w.line, w.message, report.Diagnostics)
+		}
+	}
+	for _, d := range report.Diagnostics {
+		if strings.Contains(d.Message, "requirements.md") && d.File != filepath.Join(dir, "base.yaml") {
+			t.Errorf("expected diagnostic for imported stage to point at base.yaml, got %s", d.File)
+		}
+	}
+}
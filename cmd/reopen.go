@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"specfirst/internal/app"
+)
+
+var reopenCmd = &cobra.Command{
+	Use:   "reopen",
+	Short: "Reopen a workflow closed by a terminal stage",
+	Long: `Completing a terminal stage closes the workflow: no further stages can be
+completed or skipped. Reopen clears the closure so work can continue (for
+example re-completing a stage with --force). Completing the terminal stage
+again closes the workflow once more.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		application, err := app.Load(protocolFlag)
+		if err != nil {
+			return err
+		}
+		closed, err := application.Reopen()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Reopened workflow (closed by terminal stage %s)\n", closed.Stage)
+		return nil
+	},
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"specfirst/internal/app"
+)
+
+var rerunStale bool
+
+var rerunCmd = &cobra.Command{
+	Use:   "rerun --stale",
+	Short: "List stages that need to be re-run because their inputs changed",
+	Long: `List completed stages whose recorded input artifacts no longer match the
+current upstream artifacts, along with every stage downstream of them.
+
+Stages are listed in dependency order, so re-running them top to bottom
+refreshes each stage before the stages that consume its outputs.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !rerunStale {
+			return errors.New("rerun requires --stale")
+		}
+
+		application, err := app.Load(protocolFlag)
+		if err != nil {
+			return err
+		}
+
+		stale, err := application.StaleStages()
+		if err != nil {
+			return err
+		}
+		if len(stale) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "No stale stages.")
+			return nil
+		}
+
+		for i, st := range stale {
+			fmt.Fprintf(cmd.OutOrStdout(), "%d. %s (%s)\n", i+1, st.StageID, strings.Join(st.Reasons, "; "))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rerunCmd.Flags().BoolVar(&rerunStale, "stale", false, "list stale stages in dependency order")
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -15,13 +16,17 @@ var (
 	stageNoStrict    bool
 	stageInteractive bool
 	stageDryRun      bool
+	stageCapture     bool
+	stageForce       bool
 
 	stageGranularity    string
 	stageMaxTasks       int
 	stagePreferParallel bool
 	stageRiskBias       string
 
-	protocolFlag string
+	protocolFlag   string
+	harnessFlag    string
+	harnessTimeout time.Duration
 )
 
 var rootCmd = &cobra.Command{
@@ -40,7 +45,7 @@ var rootCmd = &cobra.Command{
 			return errors.New("missing command or stage id")
 		}
 		stageID := args[0]
-		return runStage(cmd.OutOrStdout(), stageID)
+		return runStage(cmd.Context(), cmd.OutOrStdout(), stageID)
 	},
 }
 
@@ -58,7 +63,11 @@ func init() {
 	rootCmd.PersistentFlags().IntVar(&stageMaxChars, "max-chars", 0, "truncate output to max chars")
 	rootCmd.PersistentFlags().BoolVar(&stageNoStrict, "no-strict", false, "bypass dependency gating")
 	rootCmd.PersistentFlags().BoolVar(&stageDryRun, "dry-run", false, "print prompt to stdout instead of running configured harness")
+	rootCmd.PersistentFlags().StringVar(&harnessFlag, "harness", "", "harness profile to run the stage with (overrides stage and config)")
+	rootCmd.PersistentFlags().DurationVar(&harnessTimeout, "harness-timeout", 0, "kill the harness after this duration (overrides the profile timeout)")
 	rootCmd.Flags().BoolVar(&stageInteractive, "interactive", false, "generate interactive meta-prompt")
+	rootCmd.Flags().BoolVar(&stageCapture, "capture", false, "write the harness response to the stage outputs and complete the stage")
+	rootCmd.Flags().BoolVar(&stageForce, "force", false, "with --capture, overwrite an existing stage completion")
 
 	rootCmd.PersistentFlags().StringVar(&stageGranularity, "granularity", "", "task granularity: feature, story, ticket, commit")
 	rootCmd.PersistentFlags().IntVar(&stageMaxTasks, "max-tasks", 0, "maximum number of tasks to generate")
@@ -70,6 +79,9 @@ func init() {
 	_ = rootCmd.RegisterFlagCompletionFunc("protocol", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return filterPrefix(loadProtocolNames(), toComplete), cobra.ShellCompDirectiveDefault
 	})
+	_ = rootCmd.RegisterFlagCompletionFunc("harness", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return filterPrefix(loadHarnessNames(), toComplete), cobra.ShellCompDirectiveNoFileComp
+	})
 	_ = rootCmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return filterPrefix([]string{"text", "json", "yaml", "shell"}, toComplete), cobra.ShellCompDirectiveNoFileComp
 	})
@@ -86,11 +98,15 @@ func init() {
 	rootCmd.AddCommand(statusCmd)
 	rootCmd.AddCommand(completeCmd)
 	rootCmd.AddCommand(completeSpecCmd)
+	rootCmd.AddCommand(skipCmd)
+	rootCmd.AddCommand(reopenCmd)
 	rootCmd.AddCommand(lintCmd)
 	rootCmd.AddCommand(protocolCmd)
 	rootCmd.AddCommand(archiveCmd)
 	rootCmd.AddCommand(attestCmd)
 	rootCmd.AddCommand(checkCmd)
+	rootCmd.AddCommand(runsCmd)
+	rootCmd.AddCommand(rerunCmd)
 
 	// Cognitive scaffold commands
 	rootCmd.AddCommand(diffCmd)
@@ -108,4 +124,6 @@ func init() {
 	rootCmd.AddCommand(decisionCmd)
 	rootCmd.AddCommand(riskCmd)
 	rootCmd.AddCommand(disputeCmd)
+	rootCmd.AddCommand(ledgerCmd)
+	rootCmd.AddCommand(confidenceCmd)
 }
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"specfirst/internal/domain"
+	"specfirst/internal/repository"
+)
+
+var runsCmd = &cobra.Command{
+	Use:   "runs",
+	Short: "List recorded harness runs",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runs, err := repository.ListRuns()
+		if err != nil {
+			return err
+		}
+		if len(runs) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "No runs recorded.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tSTAGE\tSTARTED\tHARNESS\tEXIT\tDURATION\tPROMPT")
+		for _, run := range runs {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+				run.ID,
+				run.Stage,
+				run.StartedAt.Local().Format(time.DateTime),
+				run.Harness,
+				run.ExitCode,
+				run.Duration().Round(time.Millisecond),
+				shortHash(run.PromptHash),
+			)
+		}
+		return w.Flush()
+	},
+}
+
+var runsShowCmd = &cobra.Command{
+	Use:               "show <run-id>",
+	Short:             "Print the prompt and response of a recorded run",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: runIDCompletions,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		run, response, err := repository.LoadRun(args[0])
+		if err != nil {
+			return err
+		}
+		prompt, err := repository.LoadPrompt(run.PromptHash)
+		if err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		fmt.Fprintf(out, "Run:      %s\n", run.ID)
+		fmt.Fprintf(out, "Stage:    %s\n", run.Stage)
+		fmt.Fprintf(out, "Started:  %s\n", run.StartedAt.Local().Format(time.RFC3339))
+		fmt.Fprintf(out, "Harness:  %s\n", run.Harness)
+		fmt.Fprintf(out, "Exit:     %d\n", run.ExitCode)
+		fmt.Fprintf(out, "Duration: %s\n", run.Duration())
+		fmt.Fprintf(out, "Prompt:   %s\n", run.PromptHash)
+		if run.Error != "" {
+			fmt.Fprintf(out, "Error:    %s\n", run.Error)
+		}
+		fmt.Fprintf(out, "\n--- prompt ---\n%s", ensureTrailingNewline(prompt))
+		fmt.Fprintf(out, "\n--- response ---\n%s", ensureTrailingNewline(response))
+		return nil
+	},
+}
+
+func init() {
+	runsCmd.AddCommand(runsShowCmd)
+}
+
+// runRecordedHarness runs the harness and records the invocation in the run log.
+// Failing to record the run is reported but does not fail the stage.
+func runRecordedHarness(ctx context.Context, stageID string, harness domain.HarnessProfile, prompt string, stream io.Writer, captured bool) (string, error) {
+	promptHash, err := repository.SavePrompt(prompt)
+	if err != nil {
+		return "", err
+	}
+
+	start := time.Now()
+	response, runErr := runHarness(ctx, stageID, harness, prompt, stream)
+
+	run := domain.Run{
+		ID:         repository.NewRunID(stageID, start),
+		Stage:      stageID,
+		StartedAt:  start.UTC(),
+		Harness:    harness.Command,
+		Args:       harness.Args,
+		ExitCode:   harnessExitCode(runErr),
+		DurationMS: time.Since(start).Milliseconds(),
+		PromptHash: promptHash,
+		Captured:   captured,
+	}
+	if runErr != nil {
+		run.Error = runErr.Error()
+	}
+	if err := repository.SaveRun(run, response); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record run: %v\n", err)
+	}
+	return response, runErr
+}
+
+// harnessExitCode maps a harness error to a process exit code.
+func harnessExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+func shortHash(hash string) string {
+	const prefix = "sha256:"
+	if len(hash) > len(prefix)+12 {
+		return hash[:len(prefix)+12]
+	}
+	return hash
+}
+
+func ensureTrailingNewline(s string) string {
+	if s == "" || s[len(s)-1] == '\n' {
+		return s
+	}
+	return s + "\n"
+}
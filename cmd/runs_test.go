@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"specfirst/internal/repository"
+)
+
+func TestRunsShowPrintsRecordedRun(t *testing.T) {
+	setupCaptureWorkspace(t, `name: capture
+version: "1"
+stages:
+  - id: notes
+    name: Notes
+    template: notes.md
+`, map[string]string{"notes.md": "# Notes\n\nA long prompt body.\n"})
+	stageCapture = false
+	stageMaxChars = 12
+	t.Cleanup(func() { stageMaxChars = 0 })
+
+	var streamed bytes.Buffer
+	if err := runStage(context.Background(), &streamed, "notes"); err != nil {
+		t.Fatalf("runStage: %v", err)
+	}
+	runs, err := repository.ListRuns()
+	if err != nil || len(runs) != 1 {
+		t.Fatalf("expected 1 run, got %d (%v)", len(runs), err)
+	}
+	run := runs[0]
+
+	// The run log and the prompt store hold the one prompt the harness received.
+	entries, err := os.ReadDir(repository.GeneratedPath(repository.PromptsDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected a single stored prompt, got %d", len(entries))
+	}
+	sent, err := repository.LoadPrompt(run.PromptHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent != streamed.String() {
+		t.Fatalf("stored prompt %q differs from the prompt sent %q", sent, streamed.String())
+	}
+
+	var out bytes.Buffer
+	runsShowCmd.SetOut(&out)
+	t.Cleanup(func() { runsShowCmd.SetOut(nil) })
+	if err := runsShowCmd.RunE(runsShowCmd, []string{run.ID}); err != nil {
+		t.Fatalf("runs show: %v", err)
+	}
+	got := out.String()
+	for _, want := range []string{
+		"Run:      " + run.ID + "\n",
+		"Stage:    notes\n",
+		"Harness:  cat\n",
+		"Exit:     0\n",
+		"Prompt:   " + run.PromptHash + "\n",
+		"\n--- prompt ---\n" + ensureTrailingNewline(sent),
+		"\n--- response ---\n" + ensureTrailingNewline(sent),
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in runs show output:\n%s", want, got)
+		}
+	}
+
+	if err := runsShowCmd.RunE(runsShowCmd, []string{"notes-missing"}); err == nil {
+		t.Fatal("expected an unknown run to fail")
+	}
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"specfirst/internal/app"
+)
+
+var skipCmd = &cobra.Command{
+	Use:   "skip <stage-id>",
+	Short: "Skip an optional stage with a recorded reason",
+	Long: `Record that an optional stage will not be run. The reason, who skipped it and
+when are stored in state and preserved in archives. Skipped stages satisfy the
+dependencies of later stages and count as done for complete-spec. Completing the
+stage later clears the skip.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: stageIDCompletions,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		stageID := args[0]
+		reason, _ := cmd.Flags().GetString("reason")
+		skippedBy, _ := cmd.Flags().GetString("by")
+		if reason == "" {
+			return fmt.Errorf("--reason is required")
+		}
+		if skippedBy == "" {
+			skippedBy = os.Getenv("USER")
+		}
+		if skippedBy == "" {
+			skippedBy = os.Getenv("USERNAME")
+		}
+
+		application, err := app.Load(protocolFlag)
+		if err != nil {
+			return err
+		}
+		if err := application.SkipStage(stageID, reason, skippedBy); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Skipped stage %s: %s\n", stageID, reason)
+		return nil
+	},
+}
+
+func init() {
+	skipCmd.Flags().String("reason", "", "why the stage is being skipped (required)")
+	skipCmd.Flags().String("by", "", "who is skipping the stage (defaults to $USER)")
+}
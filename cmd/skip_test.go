@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"specfirst/internal/app"
+	"specfirst/internal/repository"
+)
+
+func TestSkipOptionalStageSatisfiesDependencies(t *testing.T) {
+	root := setupCaptureWorkspace(t, `name: capture
+version: "1"
+stages:
+  - id: requirements
+    name: Requirements
+    template: requirements.md
+    outputs: [requirements.md]
+  - id: research
+    name: Research
+    template: research.md
+    depends_on: [requirements]
+    outputs: [research.md]
+    optional: true
+  - id: design
+    name: Design
+    template: design.md
+    depends_on: [requirements, research]
+    inputs: [requirements.md, research.md]
+    outputs: [design.md]
+`, map[string]string{
+		"requirements.md": "Requirements",
+		"research.md":     "Research",
+		"design.md":       "{{ range .Inputs }}[{{ .Name }}]{{ end }}",
+	})
+	stageCapture = false
+	t.Cleanup(func() {
+		_ = skipCmd.Flags().Set("reason", "")
+		_ = skipCmd.Flags().Set("by", "")
+	})
+
+	complete := func(stageID string) {
+		t.Helper()
+		file := stageID + ".md"
+		if err := os.WriteFile(filepath.Join(root, file), []byte(stageID), 0644); err != nil {
+			t.Fatal(err)
+		}
+		application, err := app.Load("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := application.CompleteStage(context.Background(), stageID, []string{file}, false, ""); err != nil {
+			t.Fatalf("complete %s: %v", stageID, err)
+		}
+	}
+	complete("requirements")
+
+	if err := skipCmd.RunE(skipCmd, []string{"requirements"}); err == nil || !strings.Contains(err.Error(), "--reason") {
+		t.Fatalf("expected missing reason error, got %v", err)
+	}
+	_ = skipCmd.Flags().Set("reason", "prior art covers it")
+	_ = skipCmd.Flags().Set("by", "alex")
+	if err := skipCmd.RunE(skipCmd, []string{"requirements"}); err == nil || !strings.Contains(err.Error(), "not optional") {
+		t.Fatalf("expected non-optional stage to be rejected, got %v", err)
+	}
+	var out bytes.Buffer
+	skipCmd.SetOut(&out)
+	t.Cleanup(func() { skipCmd.SetOut(nil) })
+	if err := skipCmd.RunE(skipCmd, []string{"research"}); err != nil {
+		t.Fatalf("skip research: %v", err)
+	}
+
+	application, err := app.Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	skip := application.State.Skipped["research"]
+	if skip.Reason != "prior art covers it" || skip.SkippedBy != "alex" || skip.SkippedAt.IsZero() {
+		t.Fatalf("unexpected skip record: %+v", skip)
+	}
+	if application.State.CurrentStage != "design" {
+		t.Fatalf("expected design to be ready after skipping research, got %q", application.State.CurrentStage)
+	}
+	stage, _ := application.Protocol.StageByID("design")
+	prompt, err := application.CompilePrompt(stage, []string{"requirements", "research", "design"}, app.CompileOptions{})
+	if err != nil {
+		t.Fatalf("compile design: %v", err)
+	}
+	if prompt != "[requirements.md]" {
+		t.Fatalf("expected skipped stage inputs to be omitted, got %q", prompt)
+	}
+	complete("design")
+
+	out.Reset()
+	completeSpecCmd.SetOut(&out)
+	t.Cleanup(func() {
+		completeSpecCmd.SetOut(nil)
+		_ = completeSpecCmd.Flags().Set("archive", "false")
+		_ = completeSpecCmd.Flags().Set("version", "")
+	})
+	_ = completeSpecCmd.Flags().Set("archive", "true")
+	_ = completeSpecCmd.Flags().Set("version", "v1")
+	if err := completeSpecCmd.RunE(completeSpecCmd, nil); err != nil {
+		t.Fatalf("complete-spec: %v", err)
+	}
+	if got := out.String(); !strings.Contains(got, "Skipped research: prior art covers it") {
+		t.Fatalf("expected complete-spec to report the skip, got:\n%s", got)
+	}
+
+	data, err := os.ReadFile(filepath.Join(repository.ArchivesPath("v1"), "metadata.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var metadata repository.Metadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		t.Fatal(err)
+	}
+	if metadata.StagesSkipped["research"].Reason != "prior art covers it" {
+		t.Fatalf("expected archive metadata to keep the skip rationale, got %s", data)
+	}
+}
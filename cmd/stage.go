@@ -1,18 +1,24 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
-	"os/exec"
+	"os/signal"
+	"path/filepath"
 	"strings"
+	"syscall"
 
 	"specfirst/internal/app"
+	"specfirst/internal/bundle"
+	"specfirst/internal/domain"
 	"specfirst/internal/engine/prompt"
 	"specfirst/internal/repository"
+	"specfirst/internal/utils"
 )
 
-func runStage(cmdOut interface{ Write([]byte) (int, error) }, stageID string) error {
+func runStage(ctx context.Context, cmdOut interface{ Write([]byte) (int, error) }, stageID string) error {
 	application, err := app.Load(protocolFlag)
 	if err != nil {
 		return err
@@ -23,6 +29,25 @@ func runStage(cmdOut interface{ Write([]byte) (int, error) }, stageID string) er
 		return fmt.Errorf("unknown stage: %s", stageID)
 	}
 
+	if stageCapture && stageDryRun {
+		return fmt.Errorf("--capture cannot be combined with --dry-run")
+	}
+
+	var harness domain.HarnessProfile
+	useHarness := false
+	if !stageDryRun {
+		harness, useHarness, err = resolveHarness(application.Config, stage, harnessFlag)
+		if err != nil {
+			return err
+		}
+		if harnessTimeout > 0 {
+			harness.Timeout = harnessTimeout
+		}
+	}
+	if stageCapture && !useHarness {
+		return fmt.Errorf("--capture requires a configured harness (set harness in %s)", repository.ConfigPath())
+	}
+
 	if !stageNoStrict {
 		if err := application.RequireStageDependencies(stage); err != nil {
 			return err
@@ -52,18 +77,31 @@ func runStage(cmdOut interface{ Write([]byte) (int, error) }, stageID string) er
 		return err
 	}
 
+	// Store the prompt exactly as it is sent, so the hash in the run log and
+	// on a captured completion both point at it.
+	if _, err := repository.SavePrompt(formatted); err != nil {
+		return err
+	}
+
 	if stageOut != "" {
 		if err := repository.WriteOutput(stageOut, formatted); err != nil {
 			return err
 		}
 	}
 
-	if application.Config.Harness != "" && !stageDryRun {
+	if useHarness {
 		writer, ok := cmdOut.(io.Writer)
 		if !ok {
 			return fmt.Errorf("harness requires an io.Writer output")
 		}
-		return runHarness(application.Config.Harness, application.Config.HarnessArgs, formatted, writer)
+		// Forward Ctrl-C / SIGTERM to the harness instead of abandoning it.
+		ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if stageCapture {
+			return captureStage(ctx, application, stage, harness, formatted, writer)
+		}
+		_, err := runRecordedHarness(ctx, stage.ID, harness, formatted, writer, false)
+		return err
 	}
 
 	if _, err := cmdOut.Write([]byte(formatted)); err != nil {
@@ -72,59 +110,147 @@ func runStage(cmdOut interface{ Write([]byte) (int, error) }, stageID string) er
 	return nil
 }
 
-func runHarness(harness, args, prompt string, stdout io.Writer) error {
-	argList, err := splitArgs(args)
+// captureStage runs the harness, validates its response against the output
+// contract and, once it passes, writes it to the stage's declared outputs and
+// completes the stage. Existing outputs are left untouched when the stage is
+// already completed or the response fails its contract.
+func captureStage(ctx context.Context, application *app.Application, stage domain.Stage, harness domain.HarnessProfile, formatted string, out io.Writer) error {
+	if len(stage.Outputs) == 0 {
+		return fmt.Errorf("stage %q declares no outputs to capture", stage.ID)
+	}
+	if err := application.RequireOpen(); err != nil {
+		return err
+	}
+	if err := application.RequireNotCompleted(stage, stageForce); err != nil {
+		return err
+	}
+
+	response, err := runRecordedHarness(ctx, stage.ID, harness, formatted, nil, true)
 	if err != nil {
 		return err
 	}
-	cmd := exec.Command(harness, argList...)
-	cmd.Stdin = strings.NewReader(prompt)
-	cmd.Stdout = stdout
-	cmd.Stderr = os.Stderr
-	cmd.Env = os.Environ()
-	return cmd.Run()
-}
+	if strings.TrimSpace(response) == "" {
+		return fmt.Errorf("harness returned an empty response for stage %s", stage.ID)
+	}
 
-func splitArgs(value string) ([]string, error) {
-	if strings.TrimSpace(value) == "" {
-		return nil, nil
-	}
-	fields := []string{}
-	current := strings.Builder{}
-	inQuote := rune(0)
-	escaped := false
-	for _, r := range value {
-		switch {
-		case escaped:
-			current.WriteRune(r)
-			escaped = false
-		case r == '\\':
-			escaped = true
-		case inQuote != 0:
-			if r == inQuote {
-				inQuote = 0
-			} else {
-				current.WriteRune(r)
-			}
-		case r == '"' || r == '\'':
-			inQuote = r
-		case r == ' ' || r == '\t':
-			if current.Len() > 0 {
-				fields = append(fields, current.String())
-				current.Reset()
+	files, err := captureFiles(stage, response)
+	if err != nil {
+		return err
+	}
+
+	// Stage the response next to its destinations so nothing is overwritten
+	// before it has been validated.
+	written := make([]string, 0, len(files))
+	staged := make([]string, 0, len(files))
+	destinations := make(map[string]string, len(files))
+	defer func() {
+		for _, tmp := range staged {
+			_ = os.Remove(tmp)
+		}
+	}()
+	for _, f := range files {
+		resolved, err := repository.ResolveOutputPath(f.Path)
+		if err != nil {
+			return err
+		}
+		tmp, err := stageCapturedFile(resolved, f.Content)
+		if err != nil {
+			return err
+		}
+		staged = append(staged, tmp)
+		written = append(written, resolved)
+		destinations[tmp] = resolved
+	}
+
+	// Captured output is always held to its contract; --force lets
+	// CompleteStage record the override instead.
+	if violations := application.ValidateOutputContract(stage, staged); len(violations) > 0 && !stageForce {
+		for i := range violations {
+			if dest, ok := destinations[violations[i].File]; ok {
+				violations[i].File = dest
 			}
-		default:
-			current.WriteRune(r)
 		}
+		return &app.ContractError{Stage: stage.ID, Violations: violations}
+	}
+
+	captured := make(map[string]bool, len(files))
+	for i, f := range files {
+		if err := os.Rename(staged[i], written[i]); err != nil {
+			return err
+		}
+		captured[f.Path] = true
+		fmt.Fprintf(out, "Captured %d bytes to %s\n", f.Bytes, f.Path)
+	}
+	staged = nil
+	for _, output := range stage.Outputs {
+		if !strings.Contains(output, "*") && !captured[repository.NormalizeMatchPath(output)] {
+			fmt.Fprintf(os.Stderr, "Warning: response did not include declared output %s\n", output)
+		}
+	}
+
+	// Complete against the prompt the harness received so the completion
+	// records its hash.
+	promptHash, err := repository.SavePrompt(formatted)
+	if err != nil {
+		return err
+	}
+	if err := application.CompleteStage(ctx, stage.ID, written, stageForce, repository.PromptPath(promptHash)); err != nil {
+		return err
 	}
-	if escaped {
-		return nil, fmt.Errorf("invalid args: unfinished escape")
+	fmt.Fprintf(out, "Completed stage %s\n", stage.ID)
+	reportClosure(out, os.Stderr, application)
+	return nil
+}
+
+// stageCapturedFile writes content to a temporary file in the directory of
+// path, keeping its extension so contract checks treat it like the real file.
+func stageCapturedFile(path, content string) (string, error) {
+	if err := utils.EnsureDir(filepath.Dir(path)); err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), "."+strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))+".capture-*"+filepath.Ext(path))
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", err
 	}
-	if inQuote != 0 {
-		return nil, fmt.Errorf("invalid args: unterminated quote")
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", err
 	}
-	if current.Len() > 0 {
-		fields = append(fields, current.String())
+	if err := os.Chmod(f.Name(), 0644); err != nil {
+		os.Remove(f.Name())
+		return "", err
 	}
-	return fields, nil
-}
\ No newline at end of file
+	return f.Name(), nil
+}
+
+// captureFiles maps a harness response onto the stage's declared outputs.
+// Responses containing <file path="..."> blocks are split into one file per block;
+// otherwise the whole response is written to the stage's single literal output.
+func captureFiles(stage domain.Stage, response string) ([]bundle.File, error) {
+	if bundle.HasFileBlocks(response) {
+		files, err := bundle.ParseResponse(response)
+		if err != nil {
+			return nil, fmt.Errorf("parsing harness response: %w", err)
+		}
+		for _, f := range files {
+			if !isOutputMatch(stage.Outputs, f.Path) {
+				return nil, fmt.Errorf("response file %s does not match stage outputs %v", f.Path, stage.Outputs)
+			}
+		}
+		return files, nil
+	}
+
+	if len(stage.Outputs) > 1 || strings.Contains(stage.Outputs[0], "*") {
+		return nil, fmt.Errorf("stage has multiple or wildcard outputs %v; the response must wrap each artifact in a <file path=\"...\"> block", stage.Outputs)
+	}
+	return []bundle.File{{
+		Path:    repository.NormalizeMatchPath(stage.Outputs[0]),
+		Content: response,
+		Bytes:   int64(len(response)),
+	}}, nil
+}
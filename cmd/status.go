@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -22,14 +23,83 @@ var statusCmd = &cobra.Command{
 		fmt.Fprintf(cmd.OutOrStdout(), "Protocol: %s\n", application.Protocol.Name)
 		fmt.Fprintf(cmd.OutOrStdout(), "State: %s\n", repository.StatePath())
 
+		if application.State.IsClosed() {
+			closed := application.State.Closed
+			fmt.Fprintf(cmd.OutOrStdout(), "Workflow: CLOSED by terminal stage %s on %s (run 'specfirst reopen' to continue)\n", closed.Stage, closed.ClosedAt.Format("2006-01-02 15:04 MST"))
+			if closed.Archive != "" {
+				fmt.Fprintf(cmd.OutOrStdout(), "Archived as: %s\n", closed.Archive)
+			}
+			for _, issue := range closed.Issues {
+				fmt.Fprintf(cmd.OutOrStdout(), "Closed with issue: %s\n", issue)
+			}
+		} else {
+			fmt.Fprintln(cmd.OutOrStdout(), "Workflow: open")
+		}
+
 		if len(application.State.CompletedStages) == 0 {
 			fmt.Fprintln(cmd.OutOrStdout(), "Completed stages: (none)")
 		} else {
 			fmt.Fprintf(cmd.OutOrStdout(), "Completed stages: %v\n", application.State.CompletedStages)
 		}
 
-		if application.State.CurrentStage != "" {
-			fmt.Fprintf(cmd.OutOrStdout(), "Current stage: %s\n", application.State.CurrentStage)
+		if len(application.State.Skipped) > 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "Skipped stages:")
+			for _, stage := range application.Protocol.Stages {
+				skip, ok := application.State.Skipped[stage.ID]
+				if !ok {
+					continue
+				}
+				by := skip.SkippedBy
+				if by == "" {
+					by = "unknown"
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "  - %s: %s (by %s, %s)\n", stage.ID, skip.Reason, by, skip.SkippedAt.Format("2006-01-02"))
+			}
+		}
+
+		if inactive := application.InactiveStages(); len(inactive) > 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "Inactive stages (when condition not met):")
+			for _, stage := range inactive {
+				fmt.Fprintf(cmd.OutOrStdout(), "  - %s: %s\n", stage.ID, stage.When)
+			}
+		}
+
+		ready := application.ReadyStages()
+		if len(ready) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "Ready stages: (none)")
+		} else {
+			names := make([]string, 0, len(ready))
+			for _, stage := range ready {
+				name := stage.ID
+				if stage.Optional {
+					name += " (optional)"
+				}
+				names = append(names, name)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Ready stages: %s\n", strings.Join(names, ", "))
+		}
+
+		stale, err := application.StaleStages()
+		if err != nil {
+			return err
+		}
+		if len(stale) > 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "Stale stages (upstream inputs changed):")
+			for _, st := range stale {
+				fmt.Fprintf(cmd.OutOrStdout(), "  - %s: %s\n", st.StageID, strings.Join(st.Reasons, "; "))
+			}
+		}
+
+		if counts := application.LedgerCounts(); len(counts) > 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "Ledger by stage:")
+			for _, c := range counts {
+				stage := c.Stage
+				if stage == "" {
+					stage = "(no stage)"
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "  - %s: %d assumption(s) (%d open), %d question(s) (%d open), %d risk(s) (%d open), %d decision(s) (%d proposed)\n",
+					stage, c.Assumptions, c.OpenAssumptions, c.Questions, c.OpenQuestions, c.Risks, c.OpenRisks, c.Decisions, c.ProposedDecisions)
+			}
 		}
 		return nil
 	},
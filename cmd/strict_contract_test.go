@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"specfirst/internal/app"
+	"specfirst/internal/repository"
+)
+
+func TestStrictContractBlocksCompletion(t *testing.T) {
+	root := setupCaptureWorkspace(t, `name: capture
+version: "1"
+strict_contract: true
+stages:
+  - id: design
+    name: Design
+    template: design.md
+    outputs: [design.md]
+    output:
+      format: markdown
+      sections:
+        - Design > Data Model
+        - path: Risks
+          non_empty: true
+`, map[string]string{"design.md": "Design"})
+	stageCapture = false
+	t.Cleanup(func() {
+		completeForce = false
+		stageFormat = "text"
+		completeCmd.SetOut(nil)
+	})
+	if err := os.WriteFile(filepath.Join(root, "design.md"), []byte("# Design\n\n# Risks\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	completeCmd.SetOut(&out)
+	completeCmd.SetContext(context.Background())
+	stageFormat = "json"
+	err := completeCmd.RunE(completeCmd, []string{"design", "design.md"})
+	var contractErr *app.ContractError
+	if !errors.As(err, &contractErr) || len(contractErr.Violations) != 2 {
+		t.Fatalf("expected 2 contract violations, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `3:1     section "Risks" is empty`) {
+		t.Fatalf("expected located violation in report, got:\n%v", err)
+	}
+	var report app.ContractError
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil || report.Stage != "design" || len(report.Violations) != 2 {
+		t.Fatalf("expected json violation report, got %q (%v)", out.String(), err)
+	}
+
+	s, err := repository.LoadState(repository.StatePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.IsStageCompleted("design") {
+		t.Fatal("stage should not be completed after a strict contract violation")
+	}
+
+	stageFormat = "text"
+	completeForce = true
+	if err := completeCmd.RunE(completeCmd, []string{"design", "design.md"}); err != nil {
+		t.Fatalf("complete --force: %v", err)
+	}
+	s, err = repository.LoadState(repository.StatePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	override := s.StageOutputs["design"].ContractOverride
+	if override == nil || len(override.Violations) != 2 || override.At.IsZero() {
+		t.Fatalf("expected the override to be recorded, got %+v", override)
+	}
+}
@@ -0,0 +1,171 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"specfirst/internal/domain"
+	"specfirst/internal/engine/ledger"
+	"specfirst/internal/repository"
+)
+
+// ImportedADR is a decision record read by ImportADRs.
+type ImportedADR struct {
+	ledger.ADR
+	ID        string // decision ID, set when the record was added
+	File      string // project-relative path of the record
+	Duplicate bool
+}
+
+// adrDir resolves an ADR directory against the project root.
+func adrDir(dir string) string {
+	if filepath.IsAbs(dir) {
+		return dir
+	}
+	return filepath.Join(repository.BaseDir(), dir)
+}
+
+// adrFiles returns the NNNN-title.md files of an ADR directory by number. A
+// missing directory has none.
+func adrFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		if _, ok := ledger.ADRNumber(entry.Name()); ok && !entry.IsDir() {
+			files = append(files, entry.Name())
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// ExportADRs writes accepted and reversed decisions to dir as MADR files and
+// returns their paths. A decision keeps the file it was first exported to (or
+// imported from); new decisions are numbered after the highest existing
+// record. Reversed decisions are marked superseded. Records imported with
+// ImportADRs are left as they are until the decision's status changes.
+func (app *Application) ExportADRs(dir string) ([]string, error) {
+	dir = adrDir(dir)
+	files, err := adrFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	next := 1
+	for _, name := range files {
+		if n, _ := ledger.ADRNumber(name); n >= next {
+			next = n + 1
+		}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	var written []string
+	for i := range app.State.Epistemics.Decisions {
+		d := &app.State.Epistemics.Decisions[i]
+		if d.Status != "accepted" && d.Status != "reversed" {
+			continue
+		}
+		if d.ADR == "" {
+			d.ADR = ledger.ADRFileName(next, d.Text)
+			next++
+		}
+		path := filepath.Join(dir, d.ADR)
+		if d.SourceArtifact == projectFile(path) {
+			// Imported records are left as written unless the decision has
+			// since changed status, e.g. been reversed.
+			existing, err := os.ReadFile(path)
+			if err == nil && ledger.DecisionStatus(ledger.ParseADR(string(existing)).Status) == d.Status {
+				continue
+			}
+		}
+		content := ledger.RenderADR(ledger.ADR{
+			Title:        d.Text,
+			Status:       ledger.ADRStatus(d.Status),
+			Date:         d.CreatedAt,
+			Rationale:    d.Rationale,
+			Alternatives: d.Alternatives,
+		})
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return nil, err
+		}
+		written = append(written, projectFile(path))
+	}
+	if len(written) == 0 {
+		return nil, nil
+	}
+	return written, app.SaveState()
+}
+
+// ImportADRs reads the NNNN-title.md records in dir into the ledger as
+// decisions. Records already linked to a decision, or whose title matches an
+// existing decision, are skipped. Superseded, deprecated and rejected records
+// become reversed decisions; records with neither an accepted nor such a
+// status are imported as proposed.
+func (app *Application) ImportADRs(dir, user string) ([]ImportedADR, error) {
+	dir = adrDir(dir)
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("ADR directory %s not found", projectFile(dir))
+	}
+	files, err := adrFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	linked := make(map[string]bool)
+	seen := make(map[string]bool)
+	for _, d := range app.State.Epistemics.Decisions {
+		if d.ADR != "" {
+			linked[d.ADR] = true
+		}
+		seen[ledger.Normalize(d.Text)] = true
+	}
+
+	var imported []ImportedADR
+	added := false
+	for _, name := range files {
+		path := filepath.Join(dir, name)
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading ADR %s: %w", name, err)
+		}
+		adr := ledger.ParseADR(string(content))
+		if adr.Title == "" {
+			continue
+		}
+		result := ImportedADR{ADR: adr, File: projectFile(path)}
+		if linked[name] || seen[ledger.Normalize(adr.Title)] {
+			result.Duplicate = true
+			imported = append(imported, result)
+			continue
+		}
+		seen[ledger.Normalize(adr.Title)] = true
+
+		status := ledger.DecisionStatus(adr.Status)
+		result.ID = app.State.AddDecision(adr.Title, adr.Rationale, adr.Alternatives, domain.Provenance{
+			SourceArtifact: result.File,
+			CreatedBy:      user,
+		})
+		d := &app.State.Epistemics.Decisions[len(app.State.Epistemics.Decisions)-1]
+		d.Status = status
+		d.History[len(d.History)-1].Status = status
+		d.ADR = name
+		if !adr.Date.IsZero() {
+			d.CreatedAt = adr.Date
+		}
+		imported = append(imported, result)
+		added = true
+	}
+	if added {
+		return imported, app.SaveState()
+	}
+	return imported, nil
+}
@@ -17,6 +17,14 @@ type Application struct {
 	Config   domain.Config
 	Protocol domain.Protocol
 	State    domain.State
+
+	// LintRules holds the project's custom lint rules from
+	// .specfirst/lint-rules.yaml (nil when the file does not exist).
+	LintRules *domain.LintConfig
+
+	// ProtocolPath is the file the protocol was loaded from (empty when the
+	// application was not created by Load).
+	ProtocolPath string
 }
 
 // NewApplication creates a new Application instance.
@@ -74,12 +82,29 @@ func Load(protocolOverride string) (*Application, error) {
 		// For now we assume state protocol is the source of truth for what started.
 		// If config changed, we might have a mismatch.
 	}
+	application := NewApplication(cfg, proto, s)
+	application.ProtocolPath = protoPath
+
+	// 6. Load custom lint rules
+	application.LintRules, err = repository.LoadLintRules(repository.LintRulesPath())
+	if err != nil {
+		return nil, fmt.Errorf("loading lint rules: %w", err)
+	}
+
 	// Initializing CurrentStage if empty
-	if s.CurrentStage == "" && len(proto.Stages) > 0 {
-		s.CurrentStage = proto.Stages[0].ID
+	if application.State.CurrentStage == "" {
+		if ready := application.ReadyStages(); len(ready) > 0 {
+			application.State.CurrentStage = ready[0].ID
+		}
 	}
 
-	return NewApplication(cfg, proto, s), nil
+	return application, nil
+}
+
+// ReadyStages returns every active stage whose dependencies are satisfied and
+// that has not been completed or skipped yet, in dependency order.
+func (app *Application) ReadyStages() []domain.Stage {
+	return app.Protocol.ReadyStages(app.isStageSatisfied)
 }
 
 func resolveActiveProtocol(cfg domain.Config, override string) string {
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
 
 	"specfirst/internal/domain"
@@ -12,34 +11,52 @@ import (
 	"specfirst/internal/repository"
 )
 
-// Check runs all non-blocking validations (lint, tasks, approvals, outputs).
-// Returns a list of warning messages grouped by category.
-func (app *Application) Check(failOnWarnings bool) error {
-	warnings := make(map[string][]string)
-	addWarning := func(category, msg string) {
-		warnings[category] = append(warnings[category], msg)
+// Check runs all non-blocking validations (lint, tasks, approvals, outputs)
+// and returns the findings ordered by category. Severities follow the rule
+// defaults and overrides; disabled and suppressed findings are dropped.
+func (app *Application) Check() []Finding {
+	var findings []Finding
+	add := func(category, ruleID, stageID, file, msg string) {
+		if file != "" {
+			file = projectFile(file)
+		}
+		findings = append(findings, Finding{
+			Category: category,
+			RuleID:   ruleID,
+			Severity: DefaultSeverity(ruleID),
+			Stage:    stageID,
+			File:     file,
+			Message:  msg,
+		})
 	}
 
 	// 1. Protocol Drift / Missing Approvals & Outputs
 	if app.State.Protocol != "" && app.State.Protocol != app.Protocol.Name {
-		addWarning("Protocol", fmt.Sprintf("Protocol drift: state=%s protocol=%s", app.State.Protocol, app.Protocol.Name))
+		add("Protocol", RuleProtocolDrift, "", repository.StatePath(), fmt.Sprintf("Protocol drift: state=%s protocol=%s", app.State.Protocol, app.Protocol.Name))
 	}
 
 	for _, stage := range app.Protocol.Stages {
 		if stage.Intent == "review" && len(stage.Outputs) > 0 {
-			addWarning("Protocol", fmt.Sprintf("Review stage %s declares outputs", stage.ID))
+			add("Protocol", RuleReviewOutputs, stage.ID, "", fmt.Sprintf("Review stage %s declares outputs", stage.ID))
+		}
+		if _, err := stage.IsActive(app.State, app.Config); err != nil {
+			add("Conditions", RuleInvalidCondition, stage.ID, "", fmt.Sprintf("Stage %s: %v (treated as active)", stage.ID, err))
 		}
 		if !app.State.IsStageCompleted(stage.ID) {
 			continue
 		}
 
+		if override := app.State.StageOutputs[stage.ID].ContractOverride; override != nil {
+			add("Contract", RuleContractOverride, stage.ID, repository.StatePath(), fmt.Sprintf("Stage %s was completed with --force over %d contract violation(s)", stage.ID, len(override.Violations)))
+		}
+
 		// Collect stored artifact paths for wildcard matching
 		storedRel := []string{}
 		if output, ok := app.State.StageOutputs[stage.ID]; ok {
 			for _, file := range output.Files {
 				rel, err := repository.ArtifactRelFromState(file)
 				if err != nil {
-					addWarning("Artifacts", fmt.Sprintf("Invalid stored artifact path for stage %s: %s (%v)", stage.ID, file, err))
+					add("Artifacts", RuleInvalidArtifactPath, stage.ID, "", fmt.Sprintf("Invalid stored artifact path for stage %s: %s (%v)", stage.ID, file, err))
 					continue
 				}
 				// Clean up the path relative to the stage artifact root if necessary
@@ -66,34 +83,40 @@ func (app *Application) Check(failOnWarnings bool) error {
 					}
 				}
 				if !found {
-					addWarning("Outputs", fmt.Sprintf("Missing output for stage %s: %s (no stored artifacts match)", stage.ID, output))
+					add("Outputs", RuleMissingOutput, stage.ID, "", fmt.Sprintf("Missing output for stage %s: %s (no stored artifacts match)", stage.ID, output))
 				}
 				continue
 			}
 			expected := repository.ArtifactsPath(stage.ID, output)
 			if _, err := os.Stat(expected); os.IsNotExist(err) {
-				addWarning("Outputs", fmt.Sprintf("Missing output for stage %s: %s", stage.ID, expected))
-			} else if stage.Output != nil && len(stage.Output.Sections) > 0 {
-				// Check for required sections
+				add("Outputs", RuleMissingOutput, stage.ID, "", fmt.Sprintf("Missing output for stage %s: %s", stage.ID, projectFile(expected)))
+			} else if stage.Output != nil {
+				// Check for required sections and structured contracts
 				content, err := os.ReadFile(expected)
 				if err == nil {
-					sContent := string(content)
-					for _, sectionHeader := range stage.Output.Sections {
-						// Check for markdown header
-						// We check for "# Header" or "## Header"
-						if !strings.Contains(sContent, "# "+sectionHeader) && !strings.Contains(sContent, "## "+sectionHeader) {
-							addWarning("Structure", fmt.Sprintf("Missing section %q in %s", sectionHeader, expected))
-						}
+					for _, v := range sectionViolations(stage, expected, content) {
+						findings = append(findings, violationFinding("Structure", RuleMissingSection, stage.ID, v))
+					}
+					for _, v := range structuredViolations(stage, expected, content) {
+						findings = append(findings, violationFinding("Contract", RuleContractViolation, stage.ID, v))
 					}
 				}
 			}
 		}
 	}
 
+	stale, err := app.StaleStages()
+	if err != nil {
+		add("Stale", RuleStaleStage, "", "", fmt.Sprintf("Unable to check stage freshness: %v", err))
+	}
+	for _, st := range stale {
+		add("Stale", RuleStaleStage, st.StageID, "", fmt.Sprintf("Stage %s is stale: %s", st.StageID, strings.Join(st.Reasons, "; ")))
+	}
+
 	for _, approval := range app.Protocol.Approvals {
 		if app.State.IsStageCompleted(approval.Stage) {
 			if !app.State.HasAttestation(approval.Stage, approval.Role, "approved") {
-				addWarning("Approvals", fmt.Sprintf("Missing approval for stage %s (role: %s)", approval.Stage, approval.Role))
+				add("Approvals", RuleMissingApproval, approval.Stage, "", fmt.Sprintf("Missing approval for stage %s (role: %s)", approval.Stage, approval.Role))
 			}
 		}
 	}
@@ -112,7 +135,11 @@ func (app *Application) Check(failOnWarnings bool) error {
 							if err == nil {
 								taskWarnings := taskList.Validate()
 								for _, tw := range taskWarnings {
-									addWarning("Tasks", fmt.Sprintf("[%s]: %s", file, tw))
+									rule := RuleTaskList
+									if strings.HasPrefix(tw, "circular dependency") {
+										rule = RuleTaskCycle
+									}
+									add("Tasks", rule, stage.ID, artifactPath, fmt.Sprintf("[%s]: %s", file, tw))
 								}
 							}
 						}
@@ -137,50 +164,28 @@ func (app *Application) Check(failOnWarnings bool) error {
 
 		compiledPrompt, err := app.CompilePrompt(stage, stageIDs, CompileOptions{})
 		if err != nil {
-			addWarning("Prompts", fmt.Sprintf("Prompt compile (%s): %v", stage.ID, err))
+			add("Prompts", RulePromptCompile, stage.ID, repository.TemplatesPath(stage.Template), fmt.Sprintf("Prompt compile (%s): %v", stage.ID, err))
 			continue
 		}
-		schema := prompt.DefaultSchema()
-		schema.Merge(app.Protocol.Lint)
-		if stage.Prompt != nil {
-			schema.Merge(stage.Prompt.Lint)
-		}
-		result := prompt.Validate(compiledPrompt, schema)
-		for _, w := range result.Warnings {
-			addWarning("Prompts", fmt.Sprintf("Quality (%s): %s", stage.ID, w))
-		}
-		ambiguities := prompt.ContainsAmbiguity(compiledPrompt)
-		for _, a := range ambiguities {
-			addWarning("Prompts", fmt.Sprintf("Ambiguity (%s): %s", stage.ID, a))
+		result := prompt.Validate(compiledPrompt, app.lintSchema(stage))
+		for _, issue := range result.Issues {
+			label := "Quality"
+			if issue.Rule == RulePromptAmbiguity {
+				label = "Ambiguity"
+			}
+			add("Prompts", issue.Rule, stage.ID, repository.TemplatesPath(stage.Template), fmt.Sprintf("%s (%s): %s", label, stage.ID, issue.Message))
 		}
 	}
 
-	if len(warnings) == 0 {
-		fmt.Println("No issues found.")
-		return nil
-	}
-
-	fmt.Println("Warnings (advisory):")
-	var categories []string
-	for cat := range warnings {
-		categories = append(categories, cat)
-	}
-	sort.Strings(categories)
-
-	totalWarnings := 0
-	for _, cat := range categories {
-		list := warnings[cat]
-		if len(list) > 0 {
-			fmt.Printf("\n* %s (%d)\n", cat, len(list))
-			for _, w := range list {
-				fmt.Printf("  - %s\n", w)
-			}
-			totalWarnings += len(list)
+	// 4. Artifact Lint Rules
+	for _, stage := range app.Protocol.Stages {
+		if app.State.IsStageCompleted(stage.ID) {
+			findings = append(findings, app.artifactFindings(stage, prompt.ValidateArtifact)...)
 		}
 	}
 
-	if failOnWarnings {
-		return fmt.Errorf("check failed with %d warnings", totalWarnings)
-	}
-	return nil
+	findings = append(findings, app.lintConfigFindings()...)
+	findings = app.applyRuleSettings(findings)
+	SortFindings(findings)
+	return findings
 }
@@ -0,0 +1,88 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"specfirst/internal/domain"
+)
+
+// RequireOpen returns an error once a terminal stage has closed the workflow.
+func (app *Application) RequireOpen() error {
+	if !app.State.IsClosed() {
+		return nil
+	}
+	closed := app.State.Closed
+	return fmt.Errorf("workflow was closed by terminal stage %s on %s; run 'specfirst reopen' to continue", closed.Stage, closed.ClosedAt.Format("2006-01-02"))
+}
+
+// SpecCompletionIssues returns the stages that are neither completed, skipped
+// nor inactive, and the declared approvals that have not been granted.
+func (app *Application) SpecCompletionIssues() (missingStages []string, missingApprovals []string) {
+	for _, stage := range app.Protocol.Stages {
+		if !app.isStageSatisfied(stage.ID) {
+			missingStages = append(missingStages, stage.ID)
+		}
+	}
+	for _, a := range app.Protocol.Approvals {
+		if !app.State.IsStageCompleted(a.Stage) && app.isStageSatisfied(a.Stage) {
+			continue
+		}
+		if !app.State.HasAttestation(a.Stage, a.Role, "approved") {
+			missingApprovals = append(missingApprovals, fmt.Sprintf("%s (role: %s)", a.Stage, a.Role))
+		}
+	}
+	return missingStages, missingApprovals
+}
+
+// closeWorkflow marks the workflow closed by a terminal stage, records the
+// complete-spec issues found at that point and, when configured, archives the
+// workspace as the current spec version. Archive problems are recorded as
+// issues rather than failing the completion that has already been stored.
+func (app *Application) closeWorkflow(stageID string) error {
+	closure := &domain.Closure{
+		Stage:    stageID,
+		ClosedAt: time.Now().UTC(),
+	}
+	missingStages, missingApprovals := app.SpecCompletionIssues()
+	if len(missingStages) > 0 {
+		closure.Issues = append(closure.Issues, fmt.Sprintf("missing stages: %v", missingStages))
+	}
+	if len(missingApprovals) > 0 {
+		closure.Issues = append(closure.Issues, fmt.Sprintf("missing approvals: %v", missingApprovals))
+	}
+	// The archive is named before the state is saved so that the archived
+	// state.json records the same closure as the live one.
+	version := app.State.SpecVersion
+	if app.Config.ArchiveOnClose {
+		if version == "" {
+			closure.Issues = append(closure.Issues, "archive skipped: spec_version is not set")
+		} else {
+			closure.Archive = version
+		}
+	}
+	app.State.Closed = closure
+	if err := app.SaveState(); err != nil {
+		return err
+	}
+	if closure.Archive == "" {
+		return nil
+	}
+
+	if err := app.CreateSnapshot(version, nil, fmt.Sprintf("closed by terminal stage %s", stageID)); err != nil {
+		closure.Archive = ""
+		closure.Issues = append(closure.Issues, fmt.Sprintf("archive %s failed: %v", version, err))
+		return app.SaveState()
+	}
+	return nil
+}
+
+// Reopen clears the closure so stages can be completed again.
+func (app *Application) Reopen() (domain.Closure, error) {
+	if !app.State.IsClosed() {
+		return domain.Closure{}, fmt.Errorf("workflow is not closed")
+	}
+	closed := app.State.Closed
+	app.State.Closed = nil
+	return *closed, app.SaveState()
+}
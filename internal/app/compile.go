@@ -21,7 +21,7 @@ type CompileOptions struct {
 
 func (app *Application) RequireStageDependencies(stage domain.Stage) error {
 	for _, dep := range stage.DependsOn {
-		if !app.State.IsStageCompleted(dep) {
+		if !app.isStageSatisfied(dep) {
 			return fmt.Errorf("missing dependency: %s", dep)
 		}
 	}
@@ -44,6 +44,9 @@ func (app *Application) CompilePrompt(stage domain.Stage, stageIDs []string, opt
 
 			path, err := repository.ArtifactPathForInput(input, stage.DependsOn, stageIDs)
 			if err != nil {
+				if app.skippedInput(stage, input) {
+					continue
+				}
 				return "", err
 			}
 			content, err := os.ReadFile(path)
@@ -90,6 +93,15 @@ func (app *Application) CompilePrompt(stage domain.Stage, stageIDs []string, opt
 		Prompt:         stage.Prompt,
 		OutputContract: stage.Output,
 		Epistemics:     app.State.Epistemics,
+		KnownContext:   stage.Prompt == nil || stage.Prompt.KnownContext == nil || *stage.Prompt.KnownContext,
+	}
+
+	if stage.Repeatable {
+		iterations, err := app.stageIterations(stage.ID)
+		if err != nil {
+			return "", err
+		}
+		data.Iterations = iterations
 	}
 
 	templatePath := repository.TemplatesPath(stage.Template)
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"specfirst/internal/domain"
+	"specfirst/internal/engine/contract"
 	"specfirst/internal/repository"
 	"specfirst/internal/utils"
 )
@@ -25,15 +26,23 @@ func (app *Application) CompleteStage(ctx context.Context, stageID string, outpu
 		return fmt.Errorf("unknown stage: %s", stageID)
 	}
 
+	if err := app.RequireOpen(); err != nil {
+		return err
+	}
+
+	// Condition Check
+	if !force && !app.IsStageActive(stage) {
+		return fmt.Errorf("stage %s is inactive (when: %s); use --force to complete it anyway", stageID, stage.When)
+	}
+
 	// Dependency Check
 	if err := app.RequireStageDependencies(stage); err != nil {
 		return err
 	}
 
-	// Duplicate Completion Check
-	_, hasOutput := app.State.StageOutputs[stageID]
-	if (app.State.IsStageCompleted(stageID) || hasOutput) && !force {
-		return fmt.Errorf("stage %s already completed; use --force to overwrite", stageID)
+	// Duplicate Completion Check (repeatable stages record a new iteration instead)
+	if err := app.RequireNotCompleted(stage, force); err != nil {
+		return err
 	}
 
 	// Validate Outputs
@@ -41,6 +50,27 @@ func (app *Application) CompleteStage(ctx context.Context, stageID string, outpu
 		return err
 	}
 
+	// Output Contract: json/yaml contracts and strict_contract stages block
+	// completion; otherwise violations are only reported.
+	var contractOverride *domain.ContractOverride
+	if violations := app.ValidateOutputContract(stage, outputFiles); len(violations) > 0 {
+		blocking := contract.IsStructured(stage.Output) || app.Protocol.IsStrictContract(stage)
+		switch {
+		case blocking && !force:
+			return &ContractError{Stage: stageID, Violations: violations}
+		case blocking:
+			contractOverride = &domain.ContractOverride{At: time.Now().UTC()}
+			for _, v := range violations {
+				contractOverride.Violations = append(contractOverride.Violations, v.String())
+			}
+			fmt.Fprintf(os.Stderr, "Warning: overriding %d output contract violation(s) for stage %s\n", len(violations), stageID)
+		default:
+			for _, v := range violations {
+				fmt.Fprintf(os.Stderr, "Warning: %s\n", v)
+			}
+		}
+	}
+
 	// Ambiguity Gates
 	if !force {
 		if err := app.ValidateAmbiguityGates(stage); err != nil {
@@ -69,12 +99,13 @@ func (app *Application) CompleteStage(ctx context.Context, stageID string, outpu
 		}
 	}
 
-	// Handle existing files (cleanup if force)
+	// Handle existing files (cleanup if force, or when a repeatable stage iterates)
 	var oldFiles []string
-	if force {
+	replacing := force || stage.Repeatable
+	if replacing {
 		if old, exists := app.State.StageOutputs[stageID]; exists {
 			oldFiles = old.Files
-			if len(outputFiles) < len(oldFiles) {
+			if force && len(outputFiles) < len(oldFiles) {
 				fmt.Fprintf(os.Stderr, "Warning: forcing completion with %d files, but stage previously had %d files. Obsolete artifacts will be removed.\n", len(outputFiles), len(oldFiles))
 			}
 		}
@@ -105,7 +136,7 @@ func (app *Application) CompleteStage(ctx context.Context, stageID string, outpu
 	}
 
 	// Cleanup obsolete artifacts
-	if force && len(oldFiles) > 0 {
+	if replacing && len(oldFiles) > 0 {
 		newFilesMap := make(map[string]bool)
 		for _, f := range stored {
 			newFilesMap[f] = true
@@ -125,53 +156,81 @@ func (app *Application) CompleteStage(ctx context.Context, stageID string, outpu
 		return err
 	}
 
-	var promptHashValue string
+	var prompt string
 	if promptFile != "" {
-		hash, err := utils.FileHash(promptFile)
+		data, err := os.ReadFile(promptFile)
 		if err != nil {
 			return err
 		}
-		promptHashValue = hash
+		prompt = string(data)
 	} else {
 		// Use default compile
 		stageIDs := make([]string, 0, len(app.Protocol.Stages))
 		for _, s := range app.Protocol.Stages {
 			stageIDs = append(stageIDs, s.ID)
 		}
-		prompt, err := app.CompilePrompt(stage, stageIDs, CompileOptions{})
+		compiled, err := app.CompilePrompt(stage, stageIDs, CompileOptions{})
 		if err != nil {
 			return err
 		}
-		promptHashValue = utils.PromptHash(prompt)
+		prompt = compiled
+	}
+	// Keep the prompt itself so the artifact's provenance can be recovered from its hash.
+	promptHashValue, err := repository.SavePrompt(prompt)
+	if err != nil {
+		return err
+	}
+
+	// Record the upstream artifacts this completion was based on for staleness checks.
+	inputHashes, err := app.InputHashes(stage)
+	if err != nil {
+		return err
 	}
 
 	// Update State
-	app.State.StageOutputs[stageID] = domain.StageOutput{
-		CompletedAt: time.Now().UTC(),
-		Files:       stored,
-		PromptHash:  promptHashValue,
+	output := domain.StageOutput{
+		CompletedAt:      time.Now().UTC(),
+		Files:            stored,
+		PromptHash:       promptHashValue,
+		InputHashes:      inputHashes,
+		ContractOverride: contractOverride,
 	}
+	if stage.Repeatable {
+		iteration, err := app.recordIteration(stageID, output)
+		if err != nil {
+			return err
+		}
+		output.Iteration = iteration
+	}
+	app.State.StageOutputs[stageID] = output
+	delete(app.State.Skipped, stageID)
 	if !app.State.IsStageCompleted(stageID) {
 		app.State.CompletedStages = append(app.State.CompletedStages, stageID)
 	}
 
-	// Auto-Advance Stage
-	if next := app.Protocol.NextStage(stageID); next != nil {
-		// Only advance if currently at the completed stage (or previous)
-		// Logic: if current stage is <= completed stage, move to next.
-		// Detailed logic:
-		// existing logic checked if completedIndex >= currentIndex.
-		// We can infer: if we just completed stageID, and stageID is the current stage, we advance.
-		// if stageID was a past stage, we don't advance current (unless we are replaying).
-		// Simplification: If State.CurrentStage == stageID, advance.
-		if app.State.CurrentStage == stageID {
-			app.State.CurrentStage = next.ID
-		}
+	// Point the current stage at the next stage whose dependencies are now met.
+	app.State.CurrentStage = ""
+	if ready := app.ReadyStages(); len(ready) > 0 {
+		app.State.CurrentStage = ready[0].ID
 	}
 
+	// Completing a terminal stage closes the workflow.
+	if stage.Terminal {
+		return app.closeWorkflow(stageID)
+	}
 	return app.SaveState()
 }
 
+// RequireNotCompleted returns an error when a non-repeatable stage has
+// already been completed and force is not set.
+func (app *Application) RequireNotCompleted(stage domain.Stage, force bool) error {
+	_, hasOutput := app.State.StageOutputs[stage.ID]
+	if (app.State.IsStageCompleted(stage.ID) || hasOutput) && !force && !stage.Repeatable {
+		return fmt.Errorf("stage %s already completed; use --force to overwrite", stage.ID)
+	}
+	return nil
+}
+
 func (app *Application) ValidateOutputs(stage domain.Stage, outputFiles []string) error {
 	if len(stage.Outputs) == 0 {
 		return nil
@@ -183,13 +242,21 @@ func (app *Application) ValidateOutputs(stage domain.Stage, outputFiles []string
 	return nil
 }
 
+// ValidateAmbiguityGates enforces a stage's open question and risk limits.
+// Only entries raised at the gating stage or upstream of it count; entries
+// without a stage (recorded before provenance was tracked) always count.
 func (app *Application) ValidateAmbiguityGates(stage domain.Stage) error {
+	upstream := app.Protocol.Upstream(stage.ID)
+	inScope := func(p domain.Provenance) bool {
+		return p.Stage == "" || p.Stage == stage.ID || upstream[p.Stage]
+	}
+
 	// 1. Check Open Questions Limit
 	if stage.MaxOpenQuestions != nil {
 		limit := *stage.MaxOpenQuestions
 		openCount := 0
 		for _, q := range app.State.Epistemics.OpenQuestions {
-			if q.Status == "open" {
+			if q.Status == "open" && inScope(q.Provenance) {
 				openCount++
 			}
 		}
@@ -201,7 +268,7 @@ func (app *Application) ValidateAmbiguityGates(stage domain.Stage) error {
 	// 2. Check Must Resolve Tags
 	if len(stage.MustResolveTags) > 0 {
 		for _, q := range app.State.Epistemics.OpenQuestions {
-			if q.Status != "open" {
+			if q.Status != "open" || !inScope(q.Provenance) {
 				continue
 			}
 			for _, tag := range q.Tags {
@@ -219,7 +286,7 @@ func (app *Application) ValidateAmbiguityGates(stage domain.Stage) error {
 		limit := *stage.MaxHighRisksUnmitigated
 		highRiskCount := 0
 		for _, r := range app.State.Epistemics.Risks {
-			if r.Status == "mitigated" || r.Status == "accepted" {
+			if r.Status == "mitigated" || r.Status == "accepted" || !inScope(r.Provenance) {
 				continue
 			}
 			if r.Severity == "high" {
@@ -0,0 +1,38 @@
+package app
+
+import (
+	"specfirst/internal/domain"
+)
+
+// IsStageActive evaluates the stage's `when` condition against the current
+// state and config. Stages whose condition cannot be evaluated are treated as
+// active so a broken expression never silently drops work; Check reports it.
+func (app *Application) IsStageActive(stage domain.Stage) bool {
+	active, err := stage.IsActive(app.State, app.Config)
+	return active || err != nil
+}
+
+// isStageSatisfied reports whether a stage no longer blocks the workflow: it
+// was completed or skipped, or its `when` condition does not hold.
+func (app *Application) isStageSatisfied(id string) bool {
+	if app.State.IsStageSatisfied(id) {
+		return true
+	}
+	stage, ok := app.Protocol.StageByID(id)
+	return ok && !app.IsStageActive(stage)
+}
+
+// InactiveStages returns the stages that are skipped automatically because
+// their `when` condition does not hold, in declaration order.
+func (app *Application) InactiveStages() []domain.Stage {
+	var inactive []domain.Stage
+	for _, stage := range app.Protocol.Stages {
+		if app.State.IsStageCompleted(stage.ID) {
+			continue
+		}
+		if !app.IsStageActive(stage) {
+			inactive = append(inactive, stage)
+		}
+	}
+	return inactive
+}
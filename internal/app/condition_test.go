@@ -0,0 +1,64 @@
+package app
+
+import (
+	"reflect"
+	"testing"
+
+	"specfirst/internal/domain"
+)
+
+func TestConditionalStagesFollowStateAndConfig(t *testing.T) {
+	proto := domain.Protocol{
+		Name: "conditional",
+		Stages: []domain.Stage{
+			{ID: "design"},
+			{ID: "threat-model", DependsOn: []string{"design"}, When: `hasRisk "high"`},
+			{ID: "migration", DependsOn: []string{"design"}, When: `constraintMentions "database"`},
+			{ID: "implement", DependsOn: []string{"threat-model", "migration"}},
+		},
+	}
+	app := NewApplication(domain.Config{}, proto, domain.NewState("conditional"))
+	app.State.CompletedStages = []string{"design"}
+
+	readyIDs := func() []string {
+		ids := []string{}
+		for _, stage := range app.ReadyStages() {
+			ids = append(ids, stage.ID)
+		}
+		return ids
+	}
+
+	if got := readyIDs(); !reflect.DeepEqual(got, []string{"implement"}) {
+		t.Fatalf("with no risks or constraints: ready = %v, want [implement]", got)
+	}
+	implement, _ := proto.StageByID("implement")
+	if err := app.RequireStageDependencies(implement); err != nil {
+		t.Fatalf("inactive dependencies should be satisfied: %v", err)
+	}
+	if missing, _ := app.SpecCompletionIssues(); !reflect.DeepEqual(missing, []string{"implement"}) {
+		t.Fatalf("missing stages = %v, want [implement]", missing)
+	}
+
+	app.State.AddRisk("token leakage", "high", domain.Provenance{})
+	app.Config.Constraints = map[string]string{"storage": "PostgreSQL database"}
+	if got := readyIDs(); !reflect.DeepEqual(got, []string{"threat-model", "migration"}) {
+		t.Fatalf("with a high risk and a database constraint: ready = %v", got)
+	}
+	if err := app.RequireStageDependencies(implement); err == nil {
+		t.Fatal("expected active stages to gate implement")
+	}
+
+	app.State.Epistemics.Risks[0].Status = "mitigated"
+	if got := readyIDs(); !reflect.DeepEqual(got, []string{"migration"}) {
+		t.Fatalf("after mitigating the risk: ready = %v, want [migration]", got)
+	}
+}
+
+func TestParseConditionRejectsInvalidExpressions(t *testing.T) {
+	if _, err := domain.ParseCondition(`hasRisk "high"`); err != nil {
+		t.Fatalf("valid expression rejected: %v", err)
+	}
+	if _, err := domain.ParseCondition(`unknownFunc "x"`); err == nil {
+		t.Fatal("expected unknown function to be rejected")
+	}
+}
@@ -0,0 +1,95 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"specfirst/internal/domain"
+	"specfirst/internal/engine/contract"
+	"specfirst/internal/engine/markdown"
+	"specfirst/internal/repository"
+)
+
+// ValidateOutputContract checks output files against the stage's output contract.
+// Returns every violation found (empty when the files conform).
+func (app *Application) ValidateOutputContract(stage domain.Stage, outputFiles []string) []contract.Violation {
+	if stage.Output == nil {
+		return nil
+	}
+	var violations []contract.Violation
+	for _, file := range outputFiles {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			violations = append(violations, contract.Violation{File: file, Message: fmt.Sprintf("cannot read: %v", err)})
+			continue
+		}
+		violations = append(violations, structuredViolations(stage, file, content)...)
+		violations = append(violations, sectionViolations(stage, file, content)...)
+	}
+	return violations
+}
+
+// structuredViolations validates a json or yaml artifact against the stage's
+// required fields and, when the contract references one, its JSON Schema.
+func structuredViolations(stage domain.Stage, file string, content []byte) []contract.Violation {
+	if !contract.IsStructured(stage.Output) {
+		return nil
+	}
+	var schema contract.Schema
+	if stage.Output.Schema != "" {
+		loaded, err := contract.LoadSchema(repository.SchemasPath(stage.Output.Schema))
+		if err != nil {
+			return []contract.Violation{{File: file, Message: fmt.Sprintf("cannot load schema %s: %v", stage.Output.Schema, err)}}
+		}
+		schema = loaded
+	}
+	return contract.Validate(file, content, stage.Output, schema)
+}
+
+// sectionViolations checks the stage's required markdown sections.
+func sectionViolations(stage domain.Stage, file string, content []byte) []contract.Violation {
+	if stage.Output == nil {
+		return nil
+	}
+	var violations []contract.Violation
+	for _, p := range markdown.CheckSections(string(content), stage.Output.Sections) {
+		v := contract.Violation{File: file, Message: p.Message}
+		if p.Line > 0 {
+			v.Line, v.Column = p.Line, 1
+		}
+		violations = append(violations, v)
+	}
+	return violations
+}
+
+// ContractError reports the output contract violations that blocked a
+// completion.
+type ContractError struct {
+	Stage      string               `json:"stage"`
+	Violations []contract.Violation `json:"violations"`
+}
+
+// Error renders the violations grouped by file, one per line with its location.
+func (e *ContractError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "stage %s violates its output contract (%d violation(s)):", e.Stage, len(e.Violations))
+	file := ""
+	for _, v := range e.Violations {
+		if v.File != file {
+			file = v.File
+			fmt.Fprintf(&b, "\n  %s", file)
+		}
+		location := "-"
+		if v.Line > 0 {
+			location = fmt.Sprintf("%d:%d", v.Line, v.Column)
+		}
+		message := v.Message
+		if v.Field != "" {
+			message = v.Field + ": " + message
+		}
+		fmt.Fprintf(&b, "\n    %-7s %s", location, message)
+	}
+	b.WriteString("\nuse --force to complete anyway (the override is recorded in state)")
+	return b.String()
+}
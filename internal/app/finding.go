@@ -0,0 +1,89 @@
+package app
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"specfirst/internal/domain"
+	"specfirst/internal/engine/contract"
+	"specfirst/internal/engine/prompt"
+	"specfirst/internal/repository"
+)
+
+// Finding severities.
+const (
+	SeverityError   = domain.LintError
+	SeverityWarning = domain.LintWarning
+	SeverityNote    = domain.LintNote
+)
+
+// Rule IDs reported by Check. They are stable so CI tooling can key on them;
+// see Rules for their default severities.
+const (
+	RuleProtocolDrift       = "protocol-drift"
+	RuleReviewOutputs       = "review-stage-outputs"
+	RuleInvalidCondition    = "invalid-when"
+	RuleContractOverride    = "contract-override"
+	RuleInvalidArtifactPath = "invalid-artifact-path"
+	RuleMissingOutput       = "missing-output"
+	RuleMissingSection      = "missing-section"
+	RuleContractViolation   = "contract-violation"
+	RuleStaleStage          = "stale-stage"
+	RuleMissingApproval     = "missing-approval"
+	RuleTaskList            = "task-list"
+	RuleTaskCycle           = "task-cycle"
+	RulePromptCompile       = "prompt-compile"
+	RulePromptSection       = prompt.RuleMissingSection
+	RuleForbiddenPhrase     = prompt.RuleForbiddenPhrase
+	RulePromptAmbiguity     = "prompt-ambiguity"
+	RuleLintConfig          = "lint-config"
+)
+
+// Finding is a single problem reported by Check.
+type Finding struct {
+	Category string `json:"category"`
+	RuleID   string `json:"rule_id"`
+	Severity string `json:"severity"`
+	Stage    string `json:"stage,omitempty"`
+	File     string `json:"file,omitempty"` // project-relative, slash-separated
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Message  string `json:"message"`
+}
+
+// SortFindings orders findings by category, keeping their discovery order
+// within a category.
+func SortFindings(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Category < findings[j].Category
+	})
+}
+
+// projectFile returns path relative to the project root with forward slashes,
+// or path unchanged when it lies outside the project.
+func projectFile(path string) string {
+	rel, err := filepath.Rel(repository.BaseDir(), path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
+
+// violationFinding converts an output contract violation into a finding.
+func violationFinding(category, ruleID, stageID string, v contract.Violation) Finding {
+	message := v.Message
+	if v.Field != "" {
+		message = v.Field + ": " + message
+	}
+	return Finding{
+		Category: category,
+		RuleID:   ruleID,
+		Severity: DefaultSeverity(ruleID),
+		Stage:    stageID,
+		File:     projectFile(v.File),
+		Line:     v.Line,
+		Column:   v.Column,
+		Message:  message,
+	}
+}
@@ -0,0 +1,94 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"specfirst/internal/domain"
+	"specfirst/internal/engine/templating"
+	"specfirst/internal/repository"
+	"specfirst/internal/utils"
+)
+
+// recordIteration copies a repeatable stage's freshly stored artifacts into
+// .specfirst/history/<stage>/<n>/ and appends the iteration to state.
+func (app *Application) recordIteration(stageID string, output domain.StageOutput) (int, error) {
+	if app.State.Iterations == nil {
+		app.State.Iterations = make(map[string][]domain.StageOutput)
+	}
+	iteration := len(app.State.Iterations[stageID]) + 1
+	prefix := stageID + "/"
+
+	files := make([]string, 0, len(output.Files))
+	for _, file := range output.Files {
+		src, err := repository.ArtifactAbsFromState(file)
+		if err != nil {
+			return 0, err
+		}
+		rel := filepath.ToSlash(filepath.Join(stageID, strconv.Itoa(iteration), strings.TrimPrefix(file, prefix)))
+		if err := utils.CopyFile(src, repository.HistoryPath(filepath.FromSlash(rel))); err != nil {
+			return 0, fmt.Errorf("recording iteration %d of %s: %w", iteration, stageID, err)
+		}
+		files = append(files, rel)
+	}
+
+	output.Files = files
+	output.Iteration = iteration
+	app.State.Iterations[stageID] = append(app.State.Iterations[stageID], output)
+	return iteration, nil
+}
+
+// Iteration returns a recorded iteration of a repeatable stage. Zero or a
+// negative number selects the latest iteration.
+func (app *Application) Iteration(stageID string, number int) (domain.StageOutput, error) {
+	iterations := app.State.Iterations[stageID]
+	if len(iterations) == 0 {
+		return domain.StageOutput{}, fmt.Errorf("stage %s has no recorded iterations", stageID)
+	}
+	if number <= 0 {
+		return iterations[len(iterations)-1], nil
+	}
+	if number > len(iterations) {
+		return domain.StageOutput{}, fmt.Errorf("stage %s has %d iterations (requested %d)", stageID, len(iterations), number)
+	}
+	return iterations[number-1], nil
+}
+
+// IterationArtifacts reads the artifacts stored for an iteration, named
+// relative to the stage (as declared in its outputs).
+func IterationArtifacts(stageID string, iteration domain.StageOutput) ([]templating.Input, error) {
+	prefix := fmt.Sprintf("%s/%d/", stageID, iteration.Iteration)
+	artifacts := make([]templating.Input, 0, len(iteration.Files))
+	for _, file := range iteration.Files {
+		content, err := os.ReadFile(repository.HistoryPath(filepath.FromSlash(file)))
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, templating.Input{Name: strings.TrimPrefix(file, prefix), Content: string(content)})
+	}
+	return artifacts, nil
+}
+
+// stageIterations builds the template view of a stage's iteration history.
+func (app *Application) stageIterations(stageID string) ([]templating.Iteration, error) {
+	iterations := app.State.Iterations[stageID]
+	if len(iterations) == 0 {
+		return nil, nil
+	}
+	history := make([]templating.Iteration, 0, len(iterations))
+	for _, it := range iterations {
+		artifacts, err := IterationArtifacts(stageID, it)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, templating.Iteration{
+			Number:      it.Iteration,
+			CompletedAt: it.CompletedAt,
+			Artifacts:   artifacts,
+		})
+	}
+	return history, nil
+}
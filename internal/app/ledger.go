@@ -0,0 +1,202 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"specfirst/internal/domain"
+	"specfirst/internal/engine/ledger"
+	"specfirst/internal/repository"
+)
+
+// ImportedEntry is a ledger entry added (or skipped as a duplicate) by
+// ImportLedger.
+type ImportedEntry struct {
+	ledger.Entry
+	ID        string // set when the entry was added
+	Artifact  string // project-relative artifact path
+	Duplicate bool
+}
+
+// ImportLedger extracts assumptions, open questions, risks and decisions from
+// the stored artifacts of a completed stage into the epistemic ledger. Entries
+// whose text matches an existing entry of the same kind are skipped. Imported
+// entries are attributed to the stage and user and record their source
+// artifact and line; decisions are imported as proposed and risks without a
+// stated severity as medium.
+func (app *Application) ImportLedger(stageID, user string) ([]ImportedEntry, error) {
+	if _, ok := app.Protocol.StageByID(stageID); !ok {
+		return nil, fmt.Errorf("unknown stage: %s", stageID)
+	}
+	output, ok := app.State.StageOutputs[stageID]
+	if !ok || !app.State.IsStageCompleted(stageID) {
+		return nil, fmt.Errorf("stage %s has not been completed", stageID)
+	}
+
+	seen := app.ledgerTexts()
+	var imported []ImportedEntry
+	for _, file := range output.Files {
+		artifactPath, err := repository.ArtifactAbsFromState(file)
+		if err != nil {
+			return nil, err
+		}
+		content, err := os.ReadFile(artifactPath)
+		if err != nil {
+			return nil, fmt.Errorf("reading artifact %s: %w", file, err)
+		}
+		artifact := projectFile(artifactPath)
+		for _, entry := range ledger.Extract(artifactPath, string(content)) {
+			result := ImportedEntry{Entry: entry, Artifact: artifact}
+			key := entry.Kind + "\x00" + ledger.Normalize(entry.Text)
+			if seen[key] {
+				result.Duplicate = true
+			} else {
+				seen[key] = true
+				result.ID = app.addLedgerEntry(entry, domain.Provenance{
+					Stage:          stageID,
+					SourceArtifact: artifact,
+					SourceLine:     entry.Line,
+					CreatedBy:      user,
+				})
+			}
+			imported = append(imported, result)
+		}
+	}
+
+	for _, entry := range imported {
+		if !entry.Duplicate {
+			return imported, app.SaveState()
+		}
+	}
+	return imported, nil
+}
+
+// ledgerTexts returns the normalized text of every ledger entry, keyed by kind.
+func (app *Application) ledgerTexts() map[string]bool {
+	e := app.State.Epistemics
+	seen := make(map[string]bool)
+	add := func(kind, text string) {
+		seen[kind+"\x00"+ledger.Normalize(text)] = true
+	}
+	for _, a := range e.Assumptions {
+		add(domain.KindAssumption, a.Text)
+	}
+	for _, q := range e.OpenQuestions {
+		add(domain.KindQuestion, q.Text)
+	}
+	for _, r := range e.Risks {
+		add(domain.KindRisk, r.Text)
+	}
+	for _, d := range e.Decisions {
+		add(domain.KindDecision, d.Text)
+	}
+	return seen
+}
+
+// addLedgerEntry records an extracted entry linked to its source.
+func (app *Application) addLedgerEntry(entry ledger.Entry, prov domain.Provenance) string {
+	s := &app.State
+	switch entry.Kind {
+	case domain.KindAssumption:
+		return s.AddAssumption(entry.Text, entry.Owner, prov)
+	case domain.KindQuestion:
+		return s.AddOpenQuestion(entry.Text, entry.Tags, fmt.Sprintf("%s:%d", prov.SourceArtifact, prov.SourceLine), prov)
+	case domain.KindRisk:
+		severity := entry.Severity
+		if severity == "" {
+			severity = "medium"
+		}
+		id := s.AddRisk(entry.Text, severity, prov)
+		s.Epistemics.Risks[len(s.Epistemics.Risks)-1].Mitigation = entry.Mitigation
+		return id
+	default:
+		id := s.AddDecision(entry.Text, entry.Rationale, entry.Alternatives, prov)
+		d := &s.Epistemics.Decisions[len(s.Epistemics.Decisions)-1]
+		d.Status = "proposed"
+		d.History[len(d.History)-1].Status = "proposed"
+		return id
+	}
+}
+
+// LedgerCount tallies the ledger entries raised at one stage. Open counts
+// assumptions and questions still open, risks neither mitigated nor accepted,
+// and decisions still proposed.
+type LedgerCount struct {
+	Stage             string // empty for entries recorded without a stage
+	Assumptions       int
+	OpenAssumptions   int
+	Questions         int
+	OpenQuestions     int
+	Risks             int
+	OpenRisks         int
+	Decisions         int
+	ProposedDecisions int
+}
+
+// LedgerCounts returns the ledger entry counts for each stage that has
+// entries: protocol stages in declaration order, then stages the protocol no
+// longer declares, then entries without a stage.
+func (app *Application) LedgerCounts() []LedgerCount {
+	counts := make(map[string]*LedgerCount)
+	var order []string
+	get := func(stage string) *LedgerCount {
+		c, ok := counts[stage]
+		if !ok {
+			c = &LedgerCount{Stage: stage}
+			counts[stage] = c
+			order = append(order, stage)
+		}
+		return c
+	}
+	e := app.State.Epistemics
+	for _, a := range e.Assumptions {
+		c := get(a.Stage)
+		c.Assumptions++
+		if a.Status == "open" {
+			c.OpenAssumptions++
+		}
+	}
+	for _, q := range e.OpenQuestions {
+		c := get(q.Stage)
+		c.Questions++
+		if q.Status == "open" {
+			c.OpenQuestions++
+		}
+	}
+	for _, r := range e.Risks {
+		c := get(r.Stage)
+		c.Risks++
+		if r.Status != "mitigated" && r.Status != "accepted" {
+			c.OpenRisks++
+		}
+	}
+	for _, d := range e.Decisions {
+		c := get(d.Stage)
+		c.Decisions++
+		if d.Status == "proposed" {
+			c.ProposedDecisions++
+		}
+	}
+
+	rank := make(map[string]int, len(app.Protocol.Stages))
+	for i, stage := range app.Protocol.Stages {
+		rank[stage.ID] = i
+	}
+	position := func(stage string) int {
+		if stage == "" {
+			return len(rank) + 1
+		}
+		if i, ok := rank[stage]; ok {
+			return i
+		}
+		return len(rank)
+	}
+	sort.SliceStable(order, func(i, j int) bool { return position(order[i]) < position(order[j]) })
+
+	result := make([]LedgerCount, 0, len(order))
+	for _, stage := range order {
+		result = append(result, *counts[stage])
+	}
+	return result
+}
@@ -0,0 +1,123 @@
+package app
+
+import (
+	"fmt"
+	"os"
+
+	"specfirst/internal/domain"
+	"specfirst/internal/engine/prompt"
+	"specfirst/internal/repository"
+)
+
+// LintArtifacts checks the stored artifacts of completed stages (or of one
+// stage when stageID is set) against the stage's output contract, the prompt
+// vocabulary (forbidden phrases and ambiguity) and artifact-scoped rules.
+// Findings carry line numbers and follow the same severity settings as Check.
+func (app *Application) LintArtifacts(stageID string) ([]Finding, error) {
+	if stageID != "" {
+		if _, ok := app.Protocol.StageByID(stageID); !ok {
+			return nil, fmt.Errorf("unknown stage: %s", stageID)
+		}
+		if !app.State.IsStageCompleted(stageID) {
+			return nil, fmt.Errorf("stage %s has no stored artifacts (not completed)", stageID)
+		}
+	}
+
+	var findings []Finding
+	for _, stage := range app.Protocol.Stages {
+		if (stageID != "" && stage.ID != stageID) || !app.State.IsStageCompleted(stage.ID) {
+			continue
+		}
+		findings = append(findings, app.artifactFindings(stage, prompt.LintArtifact)...)
+		for _, file := range app.State.StageOutputs[stage.ID].Files {
+			artifactPath, err := repository.ArtifactAbsFromState(file)
+			if err != nil {
+				continue
+			}
+			content, err := os.ReadFile(artifactPath)
+			if err != nil {
+				continue
+			}
+			for _, v := range sectionViolations(stage, artifactPath, content) {
+				findings = append(findings, violationFinding("Structure", RuleMissingSection, stage.ID, v))
+			}
+			for _, v := range structuredViolations(stage, artifactPath, content) {
+				findings = append(findings, violationFinding("Contract", RuleContractViolation, stage.ID, v))
+			}
+		}
+	}
+	findings = app.applyRuleSettings(findings)
+	SortFindings(findings)
+	return findings, nil
+}
+
+// artifactFindings runs a lint function over every stored artifact of a stage.
+func (app *Application) artifactFindings(stage domain.Stage, lint func(string, prompt.Schema) []prompt.Issue) []Finding {
+	var findings []Finding
+	schema := app.lintSchema(stage)
+	for _, file := range app.State.StageOutputs[stage.ID].Files {
+		artifactPath, err := repository.ArtifactAbsFromState(file)
+		if err != nil {
+			continue
+		}
+		content, err := os.ReadFile(artifactPath)
+		if err != nil {
+			continue
+		}
+		for _, issue := range lint(string(content), schema) {
+			findings = append(findings, Finding{
+				Category: "Artifacts",
+				RuleID:   issue.Rule,
+				Severity: DefaultSeverity(issue.Rule),
+				Stage:    stage.ID,
+				File:     projectFile(artifactPath),
+				Line:     issue.Line,
+				Column:   issue.Column,
+				Message:  issue.Message,
+			})
+		}
+	}
+	return findings
+}
+
+// AddLintQuestions records findings as open questions tagged "lint", the rule
+// ID and the stage, with the file and line as context and source. Findings
+// already recorded (same text and context) are skipped. Returns the number
+// added.
+func (app *Application) AddLintQuestions(findings []Finding, user string) (int, error) {
+	added := 0
+	for _, f := range findings {
+		text := "Clarify: " + f.Message
+		context := f.File
+		if f.Line > 0 {
+			context = fmt.Sprintf("%s:%d", f.File, f.Line)
+		}
+		if app.hasQuestion(text, context) {
+			continue
+		}
+		tags := []string{"lint", f.RuleID}
+		if f.Stage != "" {
+			tags = append(tags, f.Stage)
+		}
+		app.State.AddOpenQuestion(text, tags, context, domain.Provenance{
+			Stage:          f.Stage,
+			SourceArtifact: f.File,
+			SourceLine:     f.Line,
+			CreatedBy:      user,
+		})
+		added++
+	}
+	if added == 0 {
+		return 0, nil
+	}
+	return added, app.SaveState()
+}
+
+func (app *Application) hasQuestion(text, context string) bool {
+	for _, q := range app.State.Epistemics.OpenQuestions {
+		if q.Text == text && q.Context == context {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package app
+
+import (
+	"reflect"
+	"testing"
+
+	"specfirst/internal/domain"
+)
+
+func TestReadyStagesFollowsDependencies(t *testing.T) {
+	proto := domain.Protocol{
+		Name: "branches",
+		Stages: []domain.Stage{
+			{ID: "requirements"},
+			{ID: "api", DependsOn: []string{"requirements"}},
+			{ID: "ui", DependsOn: []string{"requirements"}, Optional: true},
+			{ID: "plan", DependsOn: []string{"api", "ui"}},
+			{ID: "release", DependsOn: []string{"plan"}, Terminal: true},
+		},
+	}
+	app := NewApplication(domain.Config{}, proto, domain.NewState("branches"))
+
+	readyIDs := func() []string {
+		ids := []string{}
+		for _, stage := range app.ReadyStages() {
+			ids = append(ids, stage.ID)
+		}
+		return ids
+	}
+
+	steps := []struct {
+		complete string
+		want     []string
+	}{
+		{"", []string{"requirements"}},
+		{"requirements", []string{"api", "ui"}},
+		{"ui", []string{"api"}},
+		{"api", []string{"plan"}},
+		{"plan", []string{"release"}},
+		{"release", []string{}},
+	}
+	for _, step := range steps {
+		if step.complete != "" {
+			app.State.CompletedStages = append(app.State.CompletedStages, step.complete)
+		}
+		if got := readyIDs(); !reflect.DeepEqual(got, step.want) {
+			t.Fatalf("after completing %q: ready = %v, want %v", step.complete, got, step.want)
+		}
+	}
+}
+
+func TestTopologicalOrderRespectsDependencies(t *testing.T) {
+	proto := domain.Protocol{
+		Stages: []domain.Stage{
+			{ID: "plan", DependsOn: []string{"design", "requirements"}},
+			{ID: "design", DependsOn: []string{"requirements"}},
+			{ID: "requirements"},
+			{ID: "notes"},
+		},
+	}
+	order, err := proto.TopologicalOrder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"requirements", "design", "plan", "notes"}
+	if !reflect.DeepEqual(order, want) {
+		t.Fatalf("TopologicalOrder() = %v, want %v", order, want)
+	}
+}
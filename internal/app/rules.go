@@ -0,0 +1,267 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"specfirst/internal/domain"
+	"specfirst/internal/engine/prompt"
+	"specfirst/internal/repository"
+)
+
+// Rule describes a check and the severity it reports at unless overridden.
+type Rule struct {
+	ID          string
+	Severity    string
+	Description string
+}
+
+// Rules lists every rule Check can report, with its default severity.
+var Rules = []Rule{
+	{RuleProtocolDrift, SeverityError, "state was created by a different protocol"},
+	{RuleReviewOutputs, SeverityWarning, "review stage declares outputs"},
+	{RuleInvalidCondition, SeverityError, "stage when condition cannot be evaluated"},
+	{RuleContractOverride, SeverityNote, "stage was completed with --force over contract violations"},
+	{RuleInvalidArtifactPath, SeverityError, "stored artifact path is invalid"},
+	{RuleMissingOutput, SeverityError, "completed stage is missing a declared output"},
+	{RuleMissingSection, SeverityWarning, "artifact is missing a required section"},
+	{RuleContractViolation, SeverityError, "structured artifact violates its output contract"},
+	{RuleStaleStage, SeverityWarning, "stage input artifacts changed since completion"},
+	{RuleMissingApproval, SeverityError, "completed stage lacks a required approval"},
+	{RuleTaskList, SeverityWarning, "task list has missing or inconsistent fields"},
+	{RuleTaskCycle, SeverityError, "task dependencies form a cycle"},
+	{RulePromptCompile, SeverityError, "stage prompt fails to compile"},
+	{RulePromptSection, SeverityWarning, "prompt is missing a required section"},
+	{RuleForbiddenPhrase, SeverityWarning, "prompt contains a forbidden phrase"},
+	{RulePromptAmbiguity, SeverityWarning, "prompt contains vague language"},
+	{RuleLintConfig, SeverityWarning, "severity override is invalid"},
+}
+
+// DefaultSeverity returns the built-in severity of a rule (warning for rules
+// that are not registered).
+func DefaultSeverity(ruleID string) string {
+	for _, rule := range Rules {
+		if rule.ID == ruleID {
+			return rule.Severity
+		}
+	}
+	return SeverityWarning
+}
+
+// RuleDescription describes a built-in or custom rule; custom rules are
+// described by their message.
+func (app *Application) RuleDescription(ruleID string) string {
+	for _, rule := range Rules {
+		if rule.ID == ruleID {
+			return rule.Description
+		}
+	}
+	if rule, ok := app.customRule(ruleID); ok {
+		return rule.Message
+	}
+	return ruleID
+}
+
+// ProtocolFile returns the project-relative path of the protocol file, or of
+// the config file when the protocol path is unknown.
+func (app *Application) ProtocolFile() string {
+	path := app.ProtocolPath
+	if path == "" {
+		path = repository.ConfigPath()
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return projectFile(path)
+}
+
+// lintConfigs returns the lint blocks that apply to a stage, in override
+// order: the protocol, the project's rules file, then the stage's prompt lint
+// block. An empty stageID leaves out stage blocks.
+func (app *Application) lintConfigs(stageID string) []*domain.LintConfig {
+	configs := []*domain.LintConfig{app.Protocol.Lint, app.LintRules}
+	if stageID != "" {
+		if stage, ok := app.Protocol.StageByID(stageID); ok && stage.Prompt != nil {
+			configs = append(configs, stage.Prompt.Lint)
+		}
+	}
+	return configs
+}
+
+// lintSchema returns the prompt and artifact lint schema for a stage: the
+// built-in rules merged with every lint block that applies to it.
+func (app *Application) lintSchema(stage domain.Stage) prompt.Schema {
+	schema := prompt.DefaultSchema()
+	for _, cfg := range app.lintConfigs(stage.ID) {
+		schema.Merge(cfg)
+	}
+	return schema.ForStage(stage.ID)
+}
+
+// customRule finds a rule defined in the protocol, a stage or the rules file.
+func (app *Application) customRule(ruleID string) (domain.LintRule, bool) {
+	configs := []*domain.LintConfig{app.Protocol.Lint, app.LintRules}
+	for _, stage := range app.Protocol.Stages {
+		if stage.Prompt != nil {
+			configs = append(configs, stage.Prompt.Lint)
+		}
+	}
+	for _, cfg := range configs {
+		if cfg == nil {
+			continue
+		}
+		for _, rule := range cfg.Rules {
+			if rule.ID == ruleID {
+				return rule, true
+			}
+		}
+	}
+	return domain.LintRule{}, false
+}
+
+func (app *Application) isKnownRule(ruleID string) bool {
+	for _, rule := range Rules {
+		if rule.ID == ruleID {
+			return true
+		}
+	}
+	_, ok := app.customRule(ruleID)
+	return ok
+}
+
+// applyRuleSettings sets each finding's severity from the overrides (the
+// protocol lint block, the rules file, the stage's prompt lint block, then
+// config),
+// drops findings whose rule is off, and drops findings suppressed by
+// specfirst-ignore markers in their file.
+func (app *Application) applyRuleSettings(findings []Finding) []Finding {
+	markers := make(map[string]ignoreMarkers)
+	kept := findings[:0]
+	for _, f := range findings {
+		severity := app.ruleSeverity(f.RuleID, f.Stage)
+		if severity == domain.LintOff {
+			continue
+		}
+		f.Severity = severity
+		if f.File != "" {
+			m, ok := markers[f.File]
+			if !ok {
+				m = readIgnoreMarkers(f.File)
+				markers[f.File] = m
+			}
+			if m.suppresses(f.RuleID, f.Line) {
+				continue
+			}
+		}
+		kept = append(kept, f)
+	}
+	return kept
+}
+
+// ruleSeverity resolves the effective severity of a rule for a stage.
+func (app *Application) ruleSeverity(ruleID, stageID string) string {
+	severity := DefaultSeverity(ruleID)
+	if rule, ok := app.customRule(ruleID); ok {
+		severity = rule.DefaultSeverity()
+	}
+	apply := func(overrides map[string]string) {
+		if level, ok := overrides[ruleID]; ok && domain.IsLintSeverity(level) {
+			severity = level
+		}
+	}
+	for _, cfg := range app.lintConfigs(stageID) {
+		if cfg != nil {
+			apply(cfg.Severity)
+		}
+	}
+	apply(app.Config.Lint.Severity)
+	return severity
+}
+
+// lintConfigFindings reports overrides naming unknown rules or levels. Levels
+// in the protocol and rules file are already rejected when they load.
+func (app *Application) lintConfigFindings() []Finding {
+	var findings []Finding
+	check := func(source, file string, overrides map[string]string) {
+		rules := make([]string, 0, len(overrides))
+		for rule := range overrides {
+			rules = append(rules, rule)
+		}
+		sort.Strings(rules)
+		for _, rule := range rules {
+			if !app.isKnownRule(rule) {
+				findings = append(findings, Finding{Category: "Lint", RuleID: RuleLintConfig, File: file, Message: fmt.Sprintf("%s sets a severity for unknown rule %q", source, rule)})
+			}
+			if level := overrides[rule]; !domain.IsLintSeverity(level) {
+				findings = append(findings, Finding{Category: "Lint", RuleID: RuleLintConfig, File: file, Message: fmt.Sprintf("%s sets invalid severity %q for rule %q (use error, warning, note or off)", source, level, rule)})
+			}
+		}
+	}
+	if app.Protocol.Lint != nil {
+		check("protocol "+app.Protocol.Name, "", app.Protocol.Lint.Severity)
+	}
+	if app.LintRules != nil {
+		check("lint rules", projectFile(repository.LintRulesPath()), app.LintRules.Severity)
+	}
+	for _, stage := range app.Protocol.Stages {
+		if stage.Prompt != nil && stage.Prompt.Lint != nil {
+			check("stage "+stage.ID, "", stage.Prompt.Lint.Severity)
+		}
+	}
+	check("config", projectFile(repository.ConfigPath()), app.Config.Lint.Severity)
+	return findings
+}
+
+// ignoreMarkerPattern matches "specfirst-ignore: rule-a, rule-b" and
+// "specfirst-ignore-file: rule-a", typically inside a comment.
+var ignoreMarkerPattern = regexp.MustCompile(`specfirst-ignore(-file)?:\s*([a-z0-9]+(?:-[a-z0-9]+)*(?:\s*,\s*[a-z0-9]+(?:-[a-z0-9]+)*)*)`)
+
+// ignoreMarkers records the rules suppressed in one file: for the whole file,
+// or for a line (a marker covers its own line and the next).
+type ignoreMarkers struct {
+	file  map[string]bool
+	lines map[int]map[string]bool
+}
+
+func (m ignoreMarkers) suppresses(ruleID string, line int) bool {
+	if m.file[ruleID] {
+		return true
+	}
+	return line > 0 && m.lines[line][ruleID]
+}
+
+// readIgnoreMarkers scans a project-relative file for specfirst-ignore
+// markers. Unreadable files have no markers.
+func readIgnoreMarkers(file string) ignoreMarkers {
+	m := ignoreMarkers{file: map[string]bool{}, lines: map[int]map[string]bool{}}
+	path := filepath.FromSlash(file)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(repository.BaseDir(), path)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return m
+	}
+	for i, line := range strings.Split(string(content), "\n") {
+		for _, match := range ignoreMarkerPattern.FindAllStringSubmatch(line, -1) {
+			for _, rule := range strings.Split(match[2], ",") {
+				rule = strings.TrimSpace(rule)
+				if match[1] != "" {
+					m.file[rule] = true
+					continue
+				}
+				for _, n := range []int{i + 1, i + 2} {
+					if m.lines[n] == nil {
+						m.lines[n] = map[string]bool{}
+					}
+					m.lines[n][rule] = true
+				}
+			}
+		}
+	}
+	return m
+}
@@ -0,0 +1,62 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"specfirst/internal/domain"
+	"specfirst/internal/repository"
+)
+
+// SkipStage records that an optional stage will not be run. Skipped stages
+// satisfy dependency gating and complete-spec; completing the stage later
+// clears the skip.
+func (app *Application) SkipStage(stageID, reason, user string) error {
+	stage, ok := app.Protocol.StageByID(stageID)
+	if !ok {
+		return fmt.Errorf("unknown stage: %s", stageID)
+	}
+	if err := app.RequireOpen(); err != nil {
+		return err
+	}
+	if !stage.Optional {
+		return fmt.Errorf("stage %s is not optional and cannot be skipped", stageID)
+	}
+	if strings.TrimSpace(reason) == "" {
+		return fmt.Errorf("a reason is required to skip stage %s", stageID)
+	}
+	if app.State.IsStageCompleted(stageID) {
+		return fmt.Errorf("stage %s is already completed", stageID)
+	}
+
+	if app.State.Skipped == nil {
+		app.State.Skipped = make(map[string]domain.SkipRecord)
+	}
+	app.State.Skipped[stageID] = domain.SkipRecord{
+		Reason:    reason,
+		SkippedBy: user,
+		SkippedAt: time.Now().UTC(),
+	}
+
+	app.State.CurrentStage = ""
+	if ready := app.ReadyStages(); len(ready) > 0 {
+		app.State.CurrentStage = ready[0].ID
+	}
+	return app.SaveState()
+}
+
+// skippedInput reports whether input is only produced by skipped or inactive
+// stages, in which case there is no artifact to resolve.
+func (app *Application) skippedInput(stage domain.Stage, input string) bool {
+	producers := repository.InputProducers(app.Protocol, stage, input)
+	if len(producers) == 0 {
+		return false
+	}
+	for _, id := range producers {
+		if app.State.IsStageCompleted(id) || !app.isStageSatisfied(id) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,100 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"specfirst/internal/domain"
+	"specfirst/internal/repository"
+	"specfirst/internal/utils"
+)
+
+// StaleStage is a completed stage whose upstream inputs changed after it was completed.
+type StaleStage struct {
+	StageID string
+	Reasons []string
+}
+
+// InputHashes resolves a stage's declared inputs to stored artifacts and returns
+// their content hashes keyed by artifact path (relative to the artifacts root).
+// Inputs that cannot be resolved are skipped.
+func (app *Application) InputHashes(stage domain.Stage) (map[string]string, error) {
+	if len(stage.Inputs) == 0 {
+		return nil, nil
+	}
+	stageIDs := make([]string, 0, len(app.Protocol.Stages))
+	for _, s := range app.Protocol.Stages {
+		stageIDs = append(stageIDs, s.ID)
+	}
+
+	hashes := make(map[string]string, len(stage.Inputs))
+	for _, input := range stage.Inputs {
+		path, err := repository.ArtifactPathForInput(input, stage.DependsOn, stageIDs)
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(repository.ArtifactsPath(), path)
+		if err != nil {
+			return nil, err
+		}
+		hash, err := utils.FileHash(path)
+		if err != nil {
+			return nil, err
+		}
+		hashes[filepath.ToSlash(rel)] = hash
+	}
+	return hashes, nil
+}
+
+// StaleStages returns completed stages whose recorded input hashes no longer
+// match the stored upstream artifacts, plus stages downstream of them, in
+// dependency order.
+func (app *Application) StaleStages() ([]StaleStage, error) {
+	order, err := app.Protocol.TopologicalOrder()
+	if err != nil {
+		return nil, err
+	}
+
+	stale := make(map[string]bool)
+	var result []StaleStage
+	for _, id := range order {
+		if !app.State.IsStageCompleted(id) {
+			continue
+		}
+		stage, _ := app.Protocol.StageByID(id)
+		var reasons []string
+
+		output := app.State.StageOutputs[id]
+		inputs := make([]string, 0, len(output.InputHashes))
+		for rel := range output.InputHashes {
+			inputs = append(inputs, rel)
+		}
+		sort.Strings(inputs)
+		for _, rel := range inputs {
+			current, err := utils.FileHash(repository.ArtifactsPath(filepath.FromSlash(rel)))
+			if err != nil {
+				if os.IsNotExist(err) {
+					reasons = append(reasons, fmt.Sprintf("input %s was removed", rel))
+					continue
+				}
+				return nil, err
+			}
+			if current != output.InputHashes[rel] {
+				reasons = append(reasons, fmt.Sprintf("input %s changed", rel))
+			}
+		}
+		for _, dep := range stage.DependsOn {
+			if stale[dep] {
+				reasons = append(reasons, fmt.Sprintf("depends on stale stage %s", dep))
+			}
+		}
+
+		if len(reasons) > 0 {
+			stale[id] = true
+			result = append(result, StaleStage{StageID: id, Reasons: reasons})
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,87 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"specfirst/internal/domain"
+	"specfirst/internal/repository"
+)
+
+func TestStaleStagesAfterUpstreamRecompletion(t *testing.T) {
+	tmp := t.TempDir()
+	repository.SetRootDir(tmp)
+	t.Cleanup(repository.ResetRootDir)
+	// Completion resolves output paths against the working directory.
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(tmp); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	if err := os.MkdirAll(repository.TemplatesPath(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"requirements.md", "design.md", "tasks.md"} {
+		if err := os.WriteFile(repository.TemplatesPath(name), []byte("# "+name+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	proto := domain.Protocol{
+		Name: "stale",
+		Stages: []domain.Stage{
+			{ID: "requirements", Template: "requirements.md", Outputs: []string{"requirements.md"}},
+			{ID: "design", Template: "design.md", DependsOn: []string{"requirements"}, Inputs: []string{"requirements.md"}, Outputs: []string{"design.md"}},
+			{ID: "tasks", Template: "tasks.md", DependsOn: []string{"design"}, Inputs: []string{"design.md"}, Outputs: []string{"tasks.md"}},
+		},
+	}
+	app := NewApplication(domain.Config{}, proto, domain.NewState("stale"))
+
+	complete := func(stageID, file, content string, force bool) {
+		t.Helper()
+		path := filepath.Join(tmp, file)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := app.CompleteStage(context.Background(), stageID, []string{path}, force, ""); err != nil {
+			t.Fatalf("CompleteStage(%s): %v", stageID, err)
+		}
+	}
+
+	complete("requirements", "requirements.md", "v1\n", false)
+	complete("design", "design.md", "design\n", false)
+	complete("tasks", "tasks.md", "tasks\n", false)
+
+	if got := app.State.StageOutputs["design"].InputHashes; len(got) != 1 || got["requirements/requirements.md"] == "" {
+		t.Fatalf("expected design to record its input hash, got %v", got)
+	}
+
+	stale, err := app.StaleStages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stale) != 0 {
+		t.Fatalf("expected no stale stages, got %+v", stale)
+	}
+
+	complete("requirements", "requirements.md", "v2\n", true)
+
+	stale, err = app.StaleStages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []StaleStage{
+		{StageID: "design", Reasons: []string{"input requirements/requirements.md changed"}},
+		{StageID: "tasks", Reasons: []string{"depends on stale stage design"}},
+	}
+	if !reflect.DeepEqual(stale, want) {
+		t.Fatalf("StaleStages() = %+v, want %+v", stale, want)
+	}
+}
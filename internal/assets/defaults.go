@@ -253,6 +253,18 @@ framework: ""
 # harness: claude
 # harness_args: "--verbose"
 
+# Named harness profiles (optional). Set harness to a profile name to make it
+# the default; stages can pick one with "harness: <name>" in the protocol.
+# harnesses:
+#   claude:
+#     command: claude
+#     args: ["-p"]
+#     env: ["ANTHROPIC_MODEL=claude-sonnet-4-5"]
+#     timeout: 10m
+#     prompt: stdin   # stdin | argv | file
+#     retries: 2      # retry non-zero exits and timeouts
+#     retry_backoff: 5s
+
 custom_vars: {}
 constraints: {}
 `
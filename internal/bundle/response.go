@@ -0,0 +1,91 @@
+package bundle
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// fileOpenPattern matches the opening tag of a <file path="..."> block on its own line.
+var fileOpenPattern = regexp.MustCompile(`^\s*<file\s+path="([^"]*)"\s*>\s*$`)
+
+// HasFileBlocks reports whether content contains at least one <file path="..."> block.
+func HasFileBlocks(content string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		if fileOpenPattern.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseResponse splits an LLM response into files using the same
+// <file path="..."> ... </file> blocks that bundles emit for inputs.
+// Opening and closing tags must each be on their own line; text outside
+// blocks is ignored. Paths must be relative and may not traverse upwards.
+func ParseResponse(content string) ([]File, error) {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	var files []File
+	seen := make(map[string]bool)
+	var current *File
+	var body []string
+	openLine := 0
+
+	for i, line := range lines {
+		if current == nil {
+			match := fileOpenPattern.FindStringSubmatch(line)
+			if match == nil {
+				continue
+			}
+			p, err := cleanResponsePath(match[1])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			if seen[p] {
+				return nil, fmt.Errorf("line %d: duplicate file block for %s", i+1, p)
+			}
+			seen[p] = true
+			current = &File{Path: p}
+			body = nil
+			openLine = i + 1
+			continue
+		}
+
+		if strings.TrimSpace(line) == "</file>" {
+			current.Content = strings.Join(body, "\n")
+			if current.Content != "" {
+				current.Content += "\n"
+			}
+			current.Bytes = int64(len(current.Content))
+			files = append(files, *current)
+			current = nil
+			continue
+		}
+		if fileOpenPattern.MatchString(line) {
+			return nil, fmt.Errorf("line %d: nested file block inside %s (opened on line %d)", i+1, current.Path, openLine)
+		}
+		body = append(body, line)
+	}
+
+	if current != nil {
+		return nil, fmt.Errorf("unterminated file block for %s (opened on line %d)", current.Path, openLine)
+	}
+	return files, nil
+}
+
+func cleanResponsePath(value string) (string, error) {
+	normalized := strings.TrimPrefix(strings.ReplaceAll(strings.TrimSpace(value), "\\", "/"), "./")
+	if normalized == "" {
+		return "", fmt.Errorf("file block has an empty path")
+	}
+	if strings.HasPrefix(normalized, "/") || (len(normalized) >= 2 && normalized[1] == ':') {
+		return "", fmt.Errorf("file block path must be relative: %s", value)
+	}
+	clean := path.Clean(normalized)
+	if clean == "." || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("invalid file block path: %s", value)
+	}
+	return clean, nil
+}
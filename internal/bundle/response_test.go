@@ -0,0 +1,58 @@
+package bundle
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseResponse_SplitsFileBlocks(t *testing.T) {
+	content := strings.Join([]string{
+		"Here are the artifacts:",
+		`<file path="./design.md">`,
+		"# Design",
+		"",
+		"Body",
+		"</file>",
+		"",
+		`<file path="notes/api.md">`,
+		"# API",
+		"</file>",
+		"trailing chatter",
+	}, "\n")
+
+	files, err := ParseResponse(content)
+	if err != nil {
+		t.Fatalf("ParseResponse() error: %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("expected 2 files, got %d", len(files))
+	}
+	if files[0].Path != "design.md" || files[0].Content != "# Design\n\nBody\n" {
+		t.Fatalf("unexpected first file: %+v", files[0])
+	}
+	if files[1].Path != "notes/api.md" || files[1].Content != "# API\n" {
+		t.Fatalf("unexpected second file: %+v", files[1])
+	}
+}
+
+func TestParseResponse_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"unterminated", "<file path=\"a.md\">\nbody\n", "unterminated"},
+		{"nested", "<file path=\"a.md\">\n<file path=\"b.md\">\n</file>\n", "nested"},
+		{"duplicate", "<file path=\"a.md\">\n</file>\n<file path=\"a.md\">\n</file>\n", "duplicate"},
+		{"traversal", "<file path=\"../a.md\">\n</file>\n", "invalid"},
+		{"absolute", "<file path=\"/etc/passwd\">\n</file>\n", "relative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseResponse(tt.content)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
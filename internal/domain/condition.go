@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// conditionData is the value a stage's `when` expression is evaluated against.
+type conditionData struct {
+	State      State
+	Epistemics Epistemics
+	Config     Config
+}
+
+// ParseCondition compiles a `when` expression. The expression is the body of a
+// text/template action, e.g. `hasRisk "high"` or
+// `or (constraintMentions "database") (hasOpenQuestion "migration")`.
+func ParseCondition(expr string) (*template.Template, error) {
+	tmpl, err := template.New("when").
+		Option("missingkey=zero").
+		Funcs(conditionFuncs(conditionData{})).
+		Parse("{{ if " + expr + " }}true{{ end }}")
+	if err != nil {
+		return nil, fmt.Errorf("invalid when expression %q: %w", expr, err)
+	}
+	return tmpl, nil
+}
+
+// EvalCondition reports whether a `when` expression holds for the given state
+// and config. An empty expression is always true.
+func EvalCondition(expr string, s State, cfg Config) (bool, error) {
+	if strings.TrimSpace(expr) == "" {
+		return true, nil
+	}
+	tmpl, err := ParseCondition(expr)
+	if err != nil {
+		return false, err
+	}
+	data := conditionData{State: s, Epistemics: s.Epistemics, Config: cfg}
+	var out strings.Builder
+	if err := tmpl.Funcs(conditionFuncs(data)).Execute(&out, data); err != nil {
+		return false, fmt.Errorf("evaluating when expression %q: %w", expr, err)
+	}
+	return out.String() == "true", nil
+}
+
+// IsActive reports whether the stage applies to the current state and config.
+func (s Stage) IsActive(state State, cfg Config) (bool, error) {
+	return EvalCondition(s.When, state, cfg)
+}
+
+func conditionFuncs(data conditionData) template.FuncMap {
+	return template.FuncMap{
+		// hasRisk reports an open (not mitigated or accepted) risk of the given severity.
+		"hasRisk": func(severity string) bool {
+			for _, r := range data.Epistemics.Risks {
+				if r.Severity == severity && r.Status != "mitigated" && r.Status != "accepted" {
+					return true
+				}
+			}
+			return false
+		},
+		// hasOpenQuestion reports an open question carrying the given tag.
+		"hasOpenQuestion": func(tag string) bool {
+			for _, q := range data.Epistemics.OpenQuestions {
+				if q.Status != "open" {
+					continue
+				}
+				for _, t := range q.Tags {
+					if t == tag {
+						return true
+					}
+				}
+			}
+			return false
+		},
+		// constraintMentions reports a config constraint whose key or value
+		// contains text (case-insensitive).
+		"constraintMentions": func(text string) bool {
+			needle := strings.ToLower(text)
+			for key, value := range data.Config.Constraints {
+				if strings.Contains(strings.ToLower(key), needle) || strings.Contains(strings.ToLower(value), needle) {
+					return true
+				}
+			}
+			return false
+		},
+		"completed": data.State.IsStageCompleted,
+		"contains":  strings.Contains,
+		"lower":     strings.ToLower,
+	}
+}
@@ -1,12 +1,48 @@
 package domain
 
+import "time"
+
 type Config struct {
-	ProjectName string            `mapstructure:"project_name"`
-	Protocol    string            `mapstructure:"protocol"`
-	Language    string            `mapstructure:"language"`
-	Framework   string            `mapstructure:"framework"`
-	Harness     string            `mapstructure:"harness"`
-	HarnessArgs string            `mapstructure:"harness_args"`
-	CustomVars  map[string]string `mapstructure:"custom_vars"`
-	Constraints map[string]string `mapstructure:"constraints"`
+	ProjectName string                    `mapstructure:"project_name"`
+	Protocol    string                    `mapstructure:"protocol"`
+	Language    string                    `mapstructure:"language"`
+	Framework   string                    `mapstructure:"framework"`
+	Harness     string                    `mapstructure:"harness"`
+	HarnessArgs string                    `mapstructure:"harness_args"`
+	Harnesses   map[string]HarnessProfile `mapstructure:"harnesses"`
+	CustomVars  map[string]string         `mapstructure:"custom_vars"`
+	Constraints map[string]string         `mapstructure:"constraints"`
+
+	// ArchiveOnClose archives the workspace as State.SpecVersion when a
+	// terminal stage closes the workflow.
+	ArchiveOnClose bool `mapstructure:"archive_on_close"`
+
+	// Lint holds project-wide overrides for check findings.
+	Lint LintSettings `mapstructure:"lint"`
+}
+
+// LintSettings overrides the severity of check rules for this project. Config
+// overrides take precedence over the protocol's lint block.
+type LintSettings struct {
+	Severity map[string]string `mapstructure:"severity"` // rule ID -> error, warning, note or off
+}
+
+// Prompt delivery modes for harness profiles.
+const (
+	PromptViaStdin = "stdin"
+	PromptViaArgv  = "argv"
+	PromptViaFile  = "file"
+)
+
+// HarnessProfile describes how to invoke an external LLM CLI.
+type HarnessProfile struct {
+	Command string        `mapstructure:"command"`
+	Args    []string      `mapstructure:"args"`
+	Env     []string      `mapstructure:"env"` // KEY=VALUE entries added to the inherited environment
+	Timeout time.Duration `mapstructure:"timeout"`
+	Prompt  string        `mapstructure:"prompt"` // stdin (default), argv, file
+
+	// Retry policy for non-zero exits and timeouts
+	Retries      int           `mapstructure:"retries"`
+	RetryBackoff time.Duration `mapstructure:"retry_backoff"` // initial delay, doubled per attempt
 }
@@ -4,6 +4,8 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -13,54 +15,81 @@ func generateID() string {
 	return hex.EncodeToString(b)
 }
 
-func (s *State) AddAssumption(text, owner string) string {
+// record appends a change to the entry's history.
+func (p *Provenance) record(by, action, status, note string) {
+	p.History = append(p.History, LedgerUpdate{
+		At:     time.Now(),
+		By:     by,
+		Action: action,
+		Status: status,
+		Note:   note,
+	})
+}
+
+// created records the creation of an entry; imported entries (those with a
+// source artifact) are recorded as imported.
+func (p Provenance) created(status string) Provenance {
+	action := "created"
+	if p.SourceArtifact != "" {
+		action = "imported"
+	}
+	p.History = nil
+	p.record(p.CreatedBy, action, status, "")
+	return p
+}
+
+func (s *State) AddAssumption(text, owner string, prov Provenance) string {
 	id := generateID()
 	a := Assumption{
-		ID:        id,
-		Text:      text,
-		Status:    "open",
-		Owner:     owner,
-		CreatedAt: time.Now(),
+		ID:         id,
+		Text:       text,
+		Status:     "open",
+		Owner:      owner,
+		CreatedAt:  time.Now(),
+		Provenance: prov.created("open"),
 	}
 	s.Epistemics.Assumptions = append(s.Epistemics.Assumptions, a)
 	return id
 }
 
-func (s *State) CloseAssumption(id, status string) bool {
+func (s *State) CloseAssumption(id, status, by string) bool {
 	for i := range s.Epistemics.Assumptions {
 		if s.Epistemics.Assumptions[i].ID == id {
 			s.Epistemics.Assumptions[i].Status = status
+			s.Epistemics.Assumptions[i].record(by, "closed", status, "")
 			return true
 		}
 	}
 	return false
 }
 
-func (s *State) AddOpenQuestion(text string, tags []string, context string) string {
+func (s *State) AddOpenQuestion(text string, tags []string, context string, prov Provenance) string {
 	id := generateID()
 	q := OpenQuestion{
-		ID:      id,
-		Text:    text,
-		Tags:    tags,
-		Status:  "open",
-		Context: context,
+		ID:         id,
+		Text:       text,
+		Tags:       tags,
+		Status:     "open",
+		Context:    context,
+		Provenance: prov.created("open"),
 	}
 	s.Epistemics.OpenQuestions = append(s.Epistemics.OpenQuestions, q)
 	return id
 }
 
-func (s *State) ResolveOpenQuestion(id, answer string) bool {
+func (s *State) ResolveOpenQuestion(id, answer, by string) bool {
 	for i := range s.Epistemics.OpenQuestions {
 		if s.Epistemics.OpenQuestions[i].ID == id {
 			s.Epistemics.OpenQuestions[i].Status = "resolved"
 			s.Epistemics.OpenQuestions[i].Answer = answer
+			s.Epistemics.OpenQuestions[i].record(by, "resolved", "resolved", answer)
 			return true
 		}
 	}
 	return false
 }
 
-func (s *State) AddDecision(text, rationale string, alternatives []string) string {
+func (s *State) AddDecision(text, rationale string, alternatives []string, prov Provenance) string {
 	id := generateID()
 	d := Decision{
 		ID:           id,
@@ -69,38 +98,42 @@ func (s *State) AddDecision(text, rationale string, alternatives []string) strin
 		Alternatives: alternatives,
 		Status:       "accepted", // default?
 		CreatedAt:    time.Now(),
+		Provenance:   prov.created("accepted"),
 	}
 	s.Epistemics.Decisions = append(s.Epistemics.Decisions, d)
 	return id
 }
 
-func (s *State) UpdateDecision(id, status string) bool {
+func (s *State) UpdateDecision(id, status, by string) bool {
 	for i := range s.Epistemics.Decisions {
 		if s.Epistemics.Decisions[i].ID == id {
 			s.Epistemics.Decisions[i].Status = status
+			s.Epistemics.Decisions[i].record(by, "updated", status, "")
 			return true
 		}
 	}
 	return false
 }
 
-func (s *State) AddRisk(text, severity string) string {
+func (s *State) AddRisk(text, severity string, prov Provenance) string {
 	id := generateID()
 	r := Risk{
-		ID:       id,
-		Text:     text,
-		Severity: severity,
-		Status:   "open",
+		ID:         id,
+		Text:       text,
+		Severity:   severity,
+		Status:     "open",
+		Provenance: prov.created("open"),
 	}
 	s.Epistemics.Risks = append(s.Epistemics.Risks, r)
 	return id
 }
 
-func (s *State) MitigateRisk(id, mitigation, status string) bool {
+func (s *State) MitigateRisk(id, mitigation, status, by string) bool {
 	for i := range s.Epistemics.Risks {
 		if s.Epistemics.Risks[i].ID == id {
 			s.Epistemics.Risks[i].Mitigation = mitigation
 			s.Epistemics.Risks[i].Status = status
+			s.Epistemics.Risks[i].record(by, "mitigated", status, mitigation)
 			return true
 		}
 	}
@@ -118,16 +151,203 @@ func (s *State) AddDispute(topic string) string {
 	return id
 }
 
-func (s *State) ResolveDispute(id string) bool {
+// ResolveDispute marks a dispute resolved and records its outcome.
+func (s *State) ResolveDispute(id, outcome string) bool {
 	for i := range s.Epistemics.Disputes {
 		if s.Epistemics.Disputes[i].ID == id {
 			s.Epistemics.Disputes[i].Status = "resolved"
+			s.Epistemics.Disputes[i].Outcome = outcome
+			return true
+		}
+	}
+	return false
+}
+
+// AddPosition records a party's claim in a dispute.
+func (s *State) AddPosition(id, owner, claim string) bool {
+	for i := range s.Epistemics.Disputes {
+		if s.Epistemics.Disputes[i].ID == id {
+			s.Epistemics.Disputes[i].Positions = append(s.Epistemics.Disputes[i].Positions, Position{Owner: owner, Claim: claim})
+			return true
+		}
+	}
+	return false
+}
+
+// DeferOpenQuestion sets a question aside without answering it.
+func (s *State) DeferOpenQuestion(id, reason, by string) bool {
+	for i := range s.Epistemics.OpenQuestions {
+		if s.Epistemics.OpenQuestions[i].ID == id {
+			s.Epistemics.OpenQuestions[i].Status = "deferred"
+			s.Epistemics.OpenQuestions[i].record(by, "deferred", "deferred", reason)
 			return true
 		}
 	}
 	return false
 }
 
+// SetConfidence sets the overall confidence level, or a stage's when stageID
+// is not empty.
+func (s *State) SetConfidence(level, stageID string) {
+	if stageID == "" {
+		s.Epistemics.Confidence.Overall = level
+		return
+	}
+	if s.Epistemics.Confidence.ByStage == nil {
+		s.Epistemics.Confidence.ByStage = make(map[string]string)
+	}
+	s.Epistemics.Confidence.ByStage[stageID] = level
+}
+
+// Ledger entry kinds.
+const (
+	KindAssumption = "assumption"
+	KindQuestion   = "question"
+	KindDecision   = "decision"
+	KindRisk       = "risk"
+	KindDispute    = "dispute"
+)
+
+// LedgerStatuses lists the statuses each kind of ledger entry can have. The
+// first is the status of a new or reopened entry.
+var LedgerStatuses = map[string][]string{
+	KindAssumption: {"open", "validated", "invalidated"},
+	KindQuestion:   {"open", "resolved", "deferred"},
+	KindDecision:   {"proposed", "accepted", "reversed"},
+	KindRisk:       {"open", "mitigated", "accepted"},
+	KindDispute:    {"open", "resolved"},
+}
+
+// Levels are the valid risk severities and confidence levels.
+var Levels = []string{"low", "medium", "high"}
+
+// CheckValue returns an error unless value is one of allowed.
+func CheckValue(what, value string, allowed []string) error {
+	for _, a := range allowed {
+		if value == a {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid %s %q (use %s)", what, value, strings.Join(allowed, ", "))
+}
+
+// ledgerRef points at the fields every kind of ledger entry shares. Disputes
+// have no provenance.
+type ledgerRef struct {
+	text, status *string
+	prov         *Provenance
+}
+
+func (r ledgerRef) record(by, action, note string) {
+	if r.prov != nil {
+		r.prov.record(by, action, *r.status, note)
+	}
+}
+
+// ledgerEntry finds an entry by kind and ID.
+func (s *State) ledgerEntry(kind, id string) (ledgerRef, bool) {
+	e := &s.Epistemics
+	switch kind {
+	case KindAssumption:
+		for i := range e.Assumptions {
+			if a := &e.Assumptions[i]; a.ID == id {
+				return ledgerRef{&a.Text, &a.Status, &a.Provenance}, true
+			}
+		}
+	case KindQuestion:
+		for i := range e.OpenQuestions {
+			if q := &e.OpenQuestions[i]; q.ID == id {
+				return ledgerRef{&q.Text, &q.Status, &q.Provenance}, true
+			}
+		}
+	case KindDecision:
+		for i := range e.Decisions {
+			if d := &e.Decisions[i]; d.ID == id {
+				return ledgerRef{&d.Text, &d.Status, &d.Provenance}, true
+			}
+		}
+	case KindRisk:
+		for i := range e.Risks {
+			if r := &e.Risks[i]; r.ID == id {
+				return ledgerRef{&r.Text, &r.Status, &r.Provenance}, true
+			}
+		}
+	case KindDispute:
+		for i := range e.Disputes {
+			if d := &e.Disputes[i]; d.ID == id {
+				return ledgerRef{&d.Topic, &d.Status, nil}, true
+			}
+		}
+	}
+	return ledgerRef{}, false
+}
+
+// EditLedgerEntry replaces the text of an entry (a dispute's topic).
+func (s *State) EditLedgerEntry(kind, id, text, by string) bool {
+	ref, ok := s.ledgerEntry(kind, id)
+	if !ok {
+		return false
+	}
+	previous := *ref.text
+	*ref.text = text
+	ref.record(by, "edited", "was: "+previous)
+	return true
+}
+
+// ReopenLedgerEntry returns an entry to its initial status. A reopened
+// question loses its answer and a reopened dispute its outcome.
+func (s *State) ReopenLedgerEntry(kind, id, by string) bool {
+	ref, ok := s.ledgerEntry(kind, id)
+	if !ok {
+		return false
+	}
+	*ref.status = LedgerStatuses[kind][0]
+	ref.record(by, "reopened", "")
+	switch kind {
+	case KindQuestion:
+		for i := range s.Epistemics.OpenQuestions {
+			if s.Epistemics.OpenQuestions[i].ID == id {
+				s.Epistemics.OpenQuestions[i].Answer = ""
+			}
+		}
+	case KindDispute:
+		for i := range s.Epistemics.Disputes {
+			if s.Epistemics.Disputes[i].ID == id {
+				s.Epistemics.Disputes[i].Outcome = ""
+			}
+		}
+	}
+	return true
+}
+
+// RemoveLedgerEntry deletes an entry.
+func (s *State) RemoveLedgerEntry(kind, id string) bool {
+	e := &s.Epistemics
+	switch kind {
+	case KindAssumption:
+		n := len(e.Assumptions)
+		e.Assumptions = slices.DeleteFunc(e.Assumptions, func(a Assumption) bool { return a.ID == id })
+		return len(e.Assumptions) < n
+	case KindQuestion:
+		n := len(e.OpenQuestions)
+		e.OpenQuestions = slices.DeleteFunc(e.OpenQuestions, func(q OpenQuestion) bool { return q.ID == id })
+		return len(e.OpenQuestions) < n
+	case KindDecision:
+		n := len(e.Decisions)
+		e.Decisions = slices.DeleteFunc(e.Decisions, func(d Decision) bool { return d.ID == id })
+		return len(e.Decisions) < n
+	case KindRisk:
+		n := len(e.Risks)
+		e.Risks = slices.DeleteFunc(e.Risks, func(r Risk) bool { return r.ID == id })
+		return len(e.Risks) < n
+	case KindDispute:
+		n := len(e.Disputes)
+		e.Disputes = slices.DeleteFunc(e.Disputes, func(d Dispute) bool { return d.ID == id })
+		return len(e.Disputes) < n
+	}
+	return false
+}
+
 // MissingApprovals checks which required approvals are missing from state
 func MissingApprovals(required []Approval, s State) []string {
 	missing := []string{}
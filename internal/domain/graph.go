@@ -0,0 +1,106 @@
+package domain
+
+import "fmt"
+
+// TopologicalOrder returns stage IDs ordered so that every stage follows the
+// stages it depends on. Independent stages keep their declaration order.
+func (p Protocol) TopologicalOrder() ([]string, error) {
+	known := make(map[string]bool, len(p.Stages))
+	for _, stage := range p.Stages {
+		known[stage.ID] = true
+	}
+	indegree := make(map[string]int, len(p.Stages))
+	dependents := make(map[string][]string, len(p.Stages))
+	for _, stage := range p.Stages {
+		for _, dep := range stage.DependsOn {
+			if !known[dep] {
+				return nil, fmt.Errorf("stage %q depends on unknown stage %q", stage.ID, dep)
+			}
+			indegree[stage.ID]++
+			dependents[dep] = append(dependents[dep], stage.ID)
+		}
+	}
+
+	order := make([]string, 0, len(p.Stages))
+	placed := make(map[string]bool, len(p.Stages))
+	for len(order) < len(p.Stages) {
+		progressed := false
+		// Scan in declaration order so ties resolve predictably.
+		for _, stage := range p.Stages {
+			if placed[stage.ID] || indegree[stage.ID] > 0 {
+				continue
+			}
+			placed[stage.ID] = true
+			order = append(order, stage.ID)
+			for _, dependent := range dependents[stage.ID] {
+				indegree[dependent]--
+			}
+			progressed = true
+			break
+		}
+		if !progressed {
+			return nil, fmt.Errorf("circular dependency detected among stages")
+		}
+	}
+	return order, nil
+}
+
+// ReadyStages returns the stages that are not yet done but whose dependencies
+// all are, in topological order. Once a terminal stage is done the workflow
+// has finished and no stages are ready.
+func (p Protocol) ReadyStages(isDone func(id string) bool) []Stage {
+	order, err := p.TopologicalOrder()
+	if err != nil {
+		// Loaded protocols are validated to be acyclic; fall back to declaration order.
+		order = make([]string, 0, len(p.Stages))
+		for _, stage := range p.Stages {
+			order = append(order, stage.ID)
+		}
+	}
+
+	for _, stage := range p.Stages {
+		if stage.Terminal && isDone(stage.ID) {
+			return nil
+		}
+	}
+
+	var ready []Stage
+	for _, id := range order {
+		stage, _ := p.StageByID(id)
+		if isDone(id) {
+			continue
+		}
+		satisfied := true
+		for _, dep := range stage.DependsOn {
+			if !isDone(dep) {
+				satisfied = false
+				break
+			}
+		}
+		if satisfied {
+			ready = append(ready, stage)
+		}
+	}
+	return ready
+}
+
+// Upstream returns the stages a stage depends on, directly or transitively.
+func (p Protocol) Upstream(stageID string) map[string]bool {
+	upstream := make(map[string]bool)
+	pending := []string{stageID}
+	for len(pending) > 0 {
+		id := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		stage, ok := p.StageByID(id)
+		if !ok {
+			continue
+		}
+		for _, dep := range stage.DependsOn {
+			if !upstream[dep] {
+				upstream[dep] = true
+				pending = append(pending, dep)
+			}
+		}
+	}
+	return upstream
+}
@@ -1,5 +1,13 @@
 package domain
 
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
 // Protocol represents a workflow definition with stages and approvals.
 type Protocol struct {
 	Name      string      `yaml:"name"`
@@ -8,6 +16,14 @@ type Protocol struct {
 	Stages    []Stage     `yaml:"stages"`
 	Approvals []Approval  `yaml:"approvals"`
 	Lint      *LintConfig `yaml:"lint,omitempty"` // Protocol-level schema additions
+
+	// Reject completions whose artifacts violate their output contract
+	StrictContract bool `yaml:"strict_contract,omitempty"`
+
+	// Inheritance (resolved by the loader; empty on a loaded protocol)
+	Extends   string           `yaml:"extends,omitempty"`   // Base protocol whose stages are inherited
+	Overrides []map[string]any `yaml:"overrides,omitempty"` // Field-level patches to inherited stages, keyed by id
+	Remove    []string         `yaml:"remove,omitempty"`    // Inherited stage IDs to drop
 }
 
 // Stage represents a workflow step with optional type, modifiers, and contracts.
@@ -21,16 +37,27 @@ type Stage struct {
 	Inputs    []string `yaml:"inputs"`
 	Outputs   []string `yaml:"outputs"`
 
+	// Placement relative to another stage (resolved by the loader)
+	Before string `yaml:"before,omitempty"`
+	After  string `yaml:"after,omitempty"`
+
 	// Stage modifiers
 	Optional   bool `yaml:"optional,omitempty"`
 	Repeatable bool `yaml:"repeatable,omitempty"`
 	Terminal   bool `yaml:"terminal,omitempty"`
 
+	// Condition under which the stage applies; inactive stages are skipped
+	When string `yaml:"when,omitempty"`
+
 	// Prompt configuration
 	Prompt *PromptConfig `yaml:"prompt,omitempty"`
 
+	// Harness profile (from config.yaml harnesses) used to run this stage
+	Harness string `yaml:"harness,omitempty"`
+
 	// Output contract
-	Output *OutputContract `yaml:"output,omitempty"`
+	Output         *OutputContract `yaml:"output,omitempty"`
+	StrictContract *bool           `yaml:"strict_contract,omitempty"` // overrides the protocol setting
 
 	// For task_prompt type - reference to decompose stage
 	Source string `yaml:"source,omitempty"`
@@ -53,20 +80,144 @@ type PromptConfig struct {
 	RiskBias          string      `yaml:"risk_bias,omitempty"` // conservative, balanced, fast
 	Rules             []string    `yaml:"rules,omitempty"`
 	RequiredFields    []string    `yaml:"required_fields,omitempty"`
-	Lint              *LintConfig `yaml:"lint,omitempty"` // Stage-level schema additions
+	Lint              *LintConfig `yaml:"lint,omitempty"`          // Stage-level schema additions
+	KnownContext      *bool       `yaml:"known_context,omitempty"` // false leaves the ledger's Known Context block out of the prompt
 }
 
 // LintConfig defines additional validation rules for prompts.
 type LintConfig struct {
-	RequiredSections []string `yaml:"required_sections,omitempty"`
-	ForbiddenPhrases []string `yaml:"forbidden_phrases,omitempty"`
+	RequiredSections []string          `yaml:"required_sections,omitempty"`
+	ForbiddenPhrases []string          `yaml:"forbidden_phrases,omitempty"`
+	Severity         map[string]string `yaml:"severity,omitempty"` // rule ID -> error, warning, note or off
+	Rules            []LintRule        `yaml:"rules,omitempty"`
+}
+
+// LintRule is a custom check that reports text matching a regular expression
+// in prompts or artifacts.
+type LintRule struct {
+	ID       string   `yaml:"id"`
+	Pattern  string   `yaml:"pattern"` // Go regexp syntax; (?i) for case-insensitive
+	Message  string   `yaml:"message"`
+	Severity string   `yaml:"severity,omitempty"` // error, warning (default) or note
+	Scope    string   `yaml:"scope,omitempty"`    // prompt (default), artifact or all
+	Stages   []string `yaml:"stages,omitempty"`   // only check these stages (default: all)
+}
+
+// Lint rule scopes.
+const (
+	LintScopePrompt   = "prompt"
+	LintScopeArtifact = "artifact"
+	LintScopeAll      = "all"
+)
+
+var lintRuleIDPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Validate checks the rule's ID, pattern, severity and scope.
+func (r LintRule) Validate() error {
+	if !lintRuleIDPattern.MatchString(r.ID) {
+		return fmt.Errorf("rule id %q must be lowercase words separated by hyphens", r.ID)
+	}
+	if r.Pattern == "" {
+		return fmt.Errorf("rule %s: pattern is required", r.ID)
+	}
+	if _, err := regexp.Compile(r.Pattern); err != nil {
+		return fmt.Errorf("rule %s: invalid pattern: %v", r.ID, err)
+	}
+	if strings.TrimSpace(r.Message) == "" {
+		return fmt.Errorf("rule %s: message is required", r.ID)
+	}
+	if r.Severity != "" && (r.Severity == LintOff || !IsLintSeverity(r.Severity)) {
+		return fmt.Errorf("rule %s: severity must be error, warning or note (got %q)", r.ID, r.Severity)
+	}
+	switch r.Scope {
+	case "", LintScopePrompt, LintScopeArtifact, LintScopeAll:
+	default:
+		return fmt.Errorf("rule %s: scope must be prompt, artifact or all (got %q)", r.ID, r.Scope)
+	}
+	return nil
+}
+
+// DefaultSeverity returns the rule's severity, warning when unset.
+func (r LintRule) DefaultSeverity() string {
+	if r.Severity == "" {
+		return LintWarning
+	}
+	return r.Severity
+}
+
+// AppliesTo reports whether the rule checks the given scope (prompt or
+// artifact) of a stage.
+func (r LintRule) AppliesTo(scope, stageID string) bool {
+	ruleScope := r.Scope
+	if ruleScope == "" {
+		ruleScope = LintScopePrompt
+	}
+	if ruleScope != LintScopeAll && ruleScope != scope {
+		return false
+	}
+	if len(r.Stages) == 0 {
+		return true
+	}
+	for _, id := range r.Stages {
+		if id == stageID {
+			return true
+		}
+	}
+	return false
+}
+
+// Lint severity levels. LintOff disables a rule entirely.
+const (
+	LintError   = "error"
+	LintWarning = "warning"
+	LintNote    = "note"
+	LintOff     = "off"
+)
+
+// IsLintSeverity reports whether s is a valid severity override.
+func IsLintSeverity(s string) bool {
+	switch s {
+	case LintError, LintWarning, LintNote, LintOff:
+		return true
+	}
+	return false
 }
 
 // OutputContract defines expected output structure.
 type OutputContract struct {
-	Format         string   `yaml:"format,omitempty"` // markdown, yaml, json
-	Sections       []string `yaml:"sections,omitempty"`
-	RequiredFields []string `yaml:"required_fields,omitempty"`
+	Format         string        `yaml:"format,omitempty"` // markdown, yaml, json
+	Sections       []SectionRule `yaml:"sections,omitempty"`
+	RequiredFields []string      `yaml:"required_fields,omitempty"` // dotted paths, "[]" for every item (e.g. tasks[].id)
+	Schema         string        `yaml:"schema,omitempty"`          // JSON Schema file in .specfirst/schemas
+}
+
+// SectionRule requires a markdown section. It is written either as a plain
+// heading path ("Design > Data Model") or as a mapping with body constraints.
+type SectionRule struct {
+	Path      string `yaml:"path"`
+	MinLength int    `yaml:"min_length,omitempty"` // minimum characters of content, excluding whitespace at either end
+	NonEmpty  bool   `yaml:"non_empty,omitempty"`
+}
+
+func (r SectionRule) String() string {
+	return r.Path
+}
+
+func (r *SectionRule) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		r.Path = node.Value
+		return nil
+	}
+	type plain SectionRule
+	return node.Decode((*plain)(r))
+}
+
+func (r SectionRule) MarshalYAML() (any, error) {
+	if r.MinLength == 0 && !r.NonEmpty {
+		return r.Path, nil
+	}
+	type plain SectionRule
+	return plain(r), nil
 }
 
 type Approval struct {
@@ -74,6 +225,15 @@ type Approval struct {
 	Stage string `yaml:"stage"`
 }
 
+// IsStrictContract reports whether output contract violations block completion
+// of the stage. A stage-level setting takes precedence over the protocol's.
+func (p Protocol) IsStrictContract(stage Stage) bool {
+	if stage.StrictContract != nil {
+		return *stage.StrictContract
+	}
+	return p.StrictContract
+}
+
 func (p Protocol) StageByID(id string) (Stage, bool) {
 	for _, stage := range p.Stages {
 		if stage.ID == id {
@@ -83,21 +243,6 @@ func (p Protocol) StageByID(id string) (Stage, bool) {
 	return Stage{}, false
 }
 
-// NextStage returns the next stage in the sequence after the given stage ID.
-func (p Protocol) NextStage(currentID string) *Stage {
-	for i, stage := range p.Stages {
-		if stage.ID == currentID {
-			if i+1 < len(p.Stages) {
-				return &p.Stages[i+1]
-			}
-			return nil
-		}
-	}
-	// If currentID not found, maybe return first stage? Or nil.
-	// Logic in CompleteStage was: "if currentID matches, check next".
-	return nil
-}
-
 // ValidSnapshotNamePattern is the regex pattern for snapshot names.
 const ValidSnapshotNamePattern = `^[a-zA-Z0-9][a-zA-Z0-9._-]*$`
 
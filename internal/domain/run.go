@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// Run records a single harness invocation for a stage.
+type Run struct {
+	ID         string    `json:"id"`
+	Stage      string    `json:"stage"`
+	StartedAt  time.Time `json:"started_at"`
+	Harness    string    `json:"harness"`
+	Args       []string  `json:"args,omitempty"`
+	ExitCode   int       `json:"exit_code"` // -1 when the harness could not be run or was interrupted
+	DurationMS int64     `json:"duration_ms"`
+	PromptHash string    `json:"prompt_hash"`
+	Captured   bool      `json:"captured,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// Duration returns the run duration.
+func (r Run) Duration() time.Duration {
+	return time.Duration(r.DurationMS) * time.Millisecond
+}
@@ -13,6 +13,32 @@ type State struct {
 	StageOutputs    map[string]StageOutput   `json:"stage_outputs"`
 	Attestations    map[string][]Attestation `json:"attestations"`
 	Epistemics      Epistemics               `json:"epistemics,omitempty"`
+
+	// Iterations holds every completion of repeatable stages, oldest first.
+	// Files are relative to .specfirst/history.
+	Iterations map[string][]StageOutput `json:"iterations,omitempty"`
+
+	// Skipped records optional stages that were deliberately not run.
+	Skipped map[string]SkipRecord `json:"skipped,omitempty"`
+
+	// Closed is set once a terminal stage is completed. No further stages can
+	// be completed or skipped until the workflow is reopened.
+	Closed *Closure `json:"closed,omitempty"`
+}
+
+// Closure records how and when the workflow was closed.
+type Closure struct {
+	Stage    string    `json:"stage"`
+	ClosedAt time.Time `json:"closed_at"`
+	Issues   []string  `json:"issues,omitempty"`  // complete-spec problems found when closing
+	Archive  string    `json:"archive,omitempty"` // archive version created on close
+}
+
+// SkipRecord explains why an optional stage was skipped.
+type SkipRecord struct {
+	Reason    string    `json:"reason"`
+	SkippedBy string    `json:"skipped_by,omitempty"`
+	SkippedAt time.Time `json:"skipped_at"`
 }
 
 type Epistemics struct {
@@ -30,6 +56,8 @@ type Assumption struct {
 	Status    string    `json:"status"` // open, validated, invalidated
 	Owner     string    `json:"owner,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
+
+	Provenance
 }
 
 type OpenQuestion struct {
@@ -39,6 +67,8 @@ type OpenQuestion struct {
 	Status  string   `json:"status"` // open, resolved, deferred
 	Answer  string   `json:"answer,omitempty"`
 	Context string   `json:"context,omitempty"` // file or section reference
+
+	Provenance
 }
 
 type Decision struct {
@@ -48,6 +78,9 @@ type Decision struct {
 	Alternatives []string  `json:"alternatives,omitempty"`
 	Status       string    `json:"status"` // proposed, accepted, reversed
 	CreatedAt    time.Time `json:"created_at"`
+	ADR          string    `json:"adr,omitempty"` // file name of the decision's ADR
+
+	Provenance
 }
 
 type Risk struct {
@@ -56,13 +89,35 @@ type Risk struct {
 	Severity   string `json:"severity"` // low, medium, high
 	Mitigation string `json:"mitigation,omitempty"`
 	Status     string `json:"status"` // open, mitigated, accepted
+
+	Provenance
+}
+
+// Provenance records which stage raised a ledger entry, where it came from
+// and every change made to it since.
+type Provenance struct {
+	Stage          string         `json:"stage,omitempty"`
+	SourceArtifact string         `json:"source_artifact,omitempty"` // project-relative path
+	SourceLine     int            `json:"source_line,omitempty"`
+	CreatedBy      string         `json:"created_by,omitempty"`
+	History        []LedgerUpdate `json:"history,omitempty"`
+}
+
+// LedgerUpdate is one change to a ledger entry.
+type LedgerUpdate struct {
+	At     time.Time `json:"at"`
+	By     string    `json:"by,omitempty"`
+	Action string    `json:"action"`           // created, imported, closed, resolved, deferred, updated, mitigated, edited, reopened
+	Status string    `json:"status,omitempty"` // status after the change
+	Note   string    `json:"note,omitempty"`
 }
 
 type Dispute struct {
 	ID        string     `json:"id"`
 	Topic     string     `json:"topic"`
 	Positions []Position `json:"positions,omitempty"`
-	Status    string     `json:"status"` // open, resolved
+	Status    string     `json:"status"`            // open, resolved
+	Outcome   string     `json:"outcome,omitempty"` // how the dispute was resolved
 }
 
 type Position struct {
@@ -76,9 +131,21 @@ type Confidence struct {
 }
 
 type StageOutput struct {
-	CompletedAt time.Time `json:"completed_at"`
-	Files       []string  `json:"files"`
-	PromptHash  string    `json:"prompt_hash"`
+	CompletedAt time.Time         `json:"completed_at"`
+	Files       []string          `json:"files"`
+	PromptHash  string            `json:"prompt_hash"`
+	InputHashes map[string]string `json:"input_hashes,omitempty"` // artifact path -> hash of each input consumed
+	Iteration   int               `json:"iteration,omitempty"`    // set for repeatable stages
+
+	// Contract violations accepted with --force
+	ContractOverride *ContractOverride `json:"contract_override,omitempty"`
+}
+
+// ContractOverride records output contract violations that were overridden
+// when a stage was completed with --force.
+type ContractOverride struct {
+	Violations []string  `json:"violations"`
+	At         time.Time `json:"at"`
 }
 
 type Attestation struct {
@@ -120,6 +187,21 @@ func (s State) IsStageCompleted(id string) bool {
 	return false
 }
 
+func (s State) IsClosed() bool {
+	return s.Closed != nil
+}
+
+func (s State) IsStageSkipped(id string) bool {
+	_, ok := s.Skipped[id]
+	return ok
+}
+
+// IsStageSatisfied reports whether a stage no longer blocks the workflow:
+// it was either completed or skipped.
+func (s State) IsStageSatisfied(id string) bool {
+	return s.IsStageCompleted(id) || s.IsStageSkipped(id)
+}
+
 func (s *State) AddAttestation(stageID string, attestation Attestation) {
 	if s.Attestations == nil {
 		s.Attestations = make(map[string][]Attestation)
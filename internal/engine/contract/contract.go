@@ -0,0 +1,142 @@
+// Package contract validates stage artifacts against their output contract.
+package contract
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"specfirst/internal/domain"
+)
+
+// Violation is a single way an artifact fails its output contract.
+type Violation struct {
+	File    string `json:"file"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// String formats the violation as file:line:column: field: message.
+func (v Violation) String() string {
+	location := v.File
+	if v.Line > 0 {
+		location = fmt.Sprintf("%s:%d:%d", v.File, v.Line, v.Column)
+	}
+	if v.Field != "" {
+		return fmt.Sprintf("%s: %s: %s", location, v.Field, v.Message)
+	}
+	return fmt.Sprintf("%s: %s", location, v.Message)
+}
+
+// IsStructured reports whether the contract's format is parsed (json or yaml).
+func IsStructured(c *domain.OutputContract) bool {
+	return c != nil && (c.Format == "json" || c.Format == "yaml")
+}
+
+// Validate checks a json or yaml artifact: it must parse, every required field
+// path must exist, and the document must match the schema when one is given.
+// Other formats are not checked here.
+func Validate(file string, content []byte, c *domain.OutputContract, schema Schema) []Violation {
+	if !IsStructured(c) {
+		return nil
+	}
+
+	var root *node
+	var err error
+	if c.Format == "json" {
+		root, err = parseJSON(content)
+	} else {
+		root, err = parseYAML(content)
+	}
+	if err != nil {
+		v := Violation{File: file, Message: fmt.Sprintf("invalid %s: %v", c.Format, err)}
+		var perr *ParseError
+		if errors.As(err, &perr) {
+			v.Line, v.Column, v.Message = perr.Line, perr.Column, fmt.Sprintf("invalid %s: %s", c.Format, perr.Message)
+		}
+		return []Violation{v}
+	}
+
+	var violations []Violation
+	report := func(n *node, field, format string, args ...any) {
+		violations = append(violations, Violation{
+			File:    file,
+			Line:    n.line,
+			Column:  n.column,
+			Field:   field,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+	for _, path := range c.RequiredFields {
+		checkRequiredField(root, path, report)
+	}
+	schema.validate(root, "", report)
+	return violations
+}
+
+// checkRequiredField reports every place a dotted field path is missing. A
+// segment ending in "[]" applies the rest of the path to every array item,
+// and "[n]" selects a single item.
+func checkRequiredField(root *node, path string, report func(n *node, field, format string, args ...any)) {
+	type cursor struct {
+		n     *node
+		field string
+	}
+	current := []cursor{{n: root}}
+	for _, segment := range strings.Split(path, ".") {
+		name, index, each := splitSegment(segment)
+		var next []cursor
+		for _, c := range current {
+			n, field := c.n, c.field
+			if name != "" {
+				field = joinField(field, name)
+				child, ok := n.fields[name]
+				if n.kind != kindObject || !ok {
+					report(n, field, "missing required field %q", path)
+					continue
+				}
+				n = child
+			}
+			switch {
+			case each:
+				if n.kind != kindArray {
+					report(n, field, "expected array for required field %q, got %s", path, n.typeName())
+					continue
+				}
+				for i, item := range n.items {
+					next = append(next, cursor{n: item, field: fmt.Sprintf("%s[%d]", field, i)})
+				}
+			case index >= 0:
+				field = fmt.Sprintf("%s[%d]", field, index)
+				if n.kind != kindArray || index >= len(n.items) {
+					report(n, field, "missing required field %q", path)
+					continue
+				}
+				next = append(next, cursor{n: n.items[index], field: field})
+			default:
+				next = append(next, cursor{n: n, field: field})
+			}
+		}
+		current = next
+	}
+}
+
+// splitSegment parses "name", "name[]" or "name[3]".
+func splitSegment(segment string) (name string, index int, each bool) {
+	open := strings.Index(segment, "[")
+	if open < 0 || !strings.HasSuffix(segment, "]") {
+		return segment, -1, false
+	}
+	name, inner := segment[:open], segment[open+1:len(segment)-1]
+	if inner == "" {
+		return name, -1, true
+	}
+	n, err := strconv.Atoi(inner)
+	if err != nil || n < 0 {
+		return segment, -1, false
+	}
+	return name, n, false
+}
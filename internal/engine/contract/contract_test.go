@@ -0,0 +1,75 @@
+package contract
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"specfirst/internal/domain"
+)
+
+func TestValidateReportsMissingFieldsWithLocations(t *testing.T) {
+	c := &domain.OutputContract{Format: "yaml", RequiredFields: []string{"title", "tasks[].id"}}
+	content := []byte("title: Plan\ntasks:\n  - id: T1\n    name: one\n  - name: two\n")
+
+	violations := Validate("tasks.yaml", content, c, nil)
+	if len(violations) != 1 {
+		t.Fatalf("expected 1 violation, got %v", violations)
+	}
+	v := violations[0]
+	if v.Field != "tasks[1].id" || v.Line != 5 || v.Column != 5 {
+		t.Fatalf("unexpected violation location: %+v", v)
+	}
+	if got := v.String(); got != `tasks.yaml:5:5: tasks[1].id: missing required field "tasks[].id"` {
+		t.Fatalf("unexpected String(): %s", got)
+	}
+}
+
+func TestValidateReportsJSONSyntaxErrorLine(t *testing.T) {
+	c := &domain.OutputContract{Format: "json"}
+	violations := Validate("out.json", []byte("{\n  \"a\": 1,\n  \"b\": \n}\n"), c, nil)
+	if len(violations) != 1 || violations[0].Line != 4 {
+		t.Fatalf("expected a syntax error on line 4, got %+v", violations)
+	}
+}
+
+func TestValidateAgainstSchema(t *testing.T) {
+	var schema Schema
+	if err := json.Unmarshal([]byte(`{
+  "type": "object",
+  "required": ["version", "items"],
+  "additionalProperties": false,
+  "properties": {
+    "version": {"type": "integer", "minimum": 1},
+    "items": {"type": "array", "minItems": 1, "items": {
+      "type": "object",
+      "properties": {"status": {"enum": ["open", "done"]}}
+    }}
+  }
+}`), &schema); err != nil {
+		t.Fatal(err)
+	}
+	c := &domain.OutputContract{Format: "json"}
+	content := []byte(`{
+  "version": 0,
+  "items": [
+    {"status": "open"},
+    {"status": "blocked"}
+  ],
+  "extra": true
+}`)
+
+	violations := Validate("plan.json", content, c, schema)
+	var got []string
+	for _, v := range violations {
+		got = append(got, v.String())
+	}
+	want := []string{
+		"plan.json:2:14: version: value 0 is less than minimum 1",
+		`plan.json:5:16: items[1].status: value blocked is not one of [open done]`,
+		`plan.json:7:12: extra: property "extra" is not allowed`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("violations:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
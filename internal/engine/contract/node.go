@@ -0,0 +1,254 @@
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Node kinds of a parsed structured document.
+const (
+	kindScalar = iota
+	kindObject
+	kindArray
+)
+
+// node is a format-independent document tree that remembers where each value
+// starts in its source file.
+type node struct {
+	kind   int
+	value  any // scalar value: string, float64, bool or nil
+	keys   []string
+	fields map[string]*node
+	items  []*node
+	line   int
+	column int
+}
+
+// ParseError is a syntax error with the position it was found at.
+type ParseError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *ParseError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+	}
+	return e.Message
+}
+
+// parseJSON decodes a JSON document into a node tree.
+func parseJSON(content []byte) (*node, error) {
+	dec := json.NewDecoder(bytes.NewReader(content))
+	dec.UseNumber()
+	p := jsonParser{dec: dec, content: content}
+	root, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		line, col := p.position(int(dec.InputOffset()))
+		return nil, &ParseError{Line: line, Column: col, Message: "unexpected data after top-level value"}
+	}
+	return root, nil
+}
+
+type jsonParser struct {
+	dec     *json.Decoder
+	content []byte
+}
+
+// start returns the line and column of the next token.
+func (p *jsonParser) start() (int, int) {
+	offset := int(p.dec.InputOffset())
+	for offset < len(p.content) {
+		switch p.content[offset] {
+		case ' ', '\t', '\r', '\n', ':', ',':
+			offset++
+			continue
+		}
+		break
+	}
+	return p.position(offset)
+}
+
+func (p *jsonParser) position(offset int) (int, int) {
+	line, col := 1, 1
+	for i := 0; i < offset && i < len(p.content); i++ {
+		if p.content[i] == '\n' {
+			line++
+			col = 1
+		} else {
+			col++
+		}
+	}
+	return line, col
+}
+
+func (p *jsonParser) syntaxError(err error) error {
+	var syntax *json.SyntaxError
+	if errors.As(err, &syntax) {
+		line, col := p.position(int(syntax.Offset))
+		return &ParseError{Line: line, Column: col, Message: syntax.Error()}
+	}
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		line, col := p.position(len(p.content))
+		return &ParseError{Line: line, Column: col, Message: "unexpected end of JSON input"}
+	}
+	return &ParseError{Message: err.Error()}
+}
+
+func (p *jsonParser) value() (*node, error) {
+	line, col := p.start()
+	tok, err := p.dec.Token()
+	if err != nil {
+		return nil, p.syntaxError(err)
+	}
+	n := &node{line: line, column: col}
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			n.kind = kindObject
+			n.fields = make(map[string]*node)
+			for p.dec.More() {
+				keyTok, err := p.dec.Token()
+				if err != nil {
+					return nil, p.syntaxError(err)
+				}
+				key, _ := keyTok.(string)
+				child, err := p.value()
+				if err != nil {
+					return nil, err
+				}
+				if _, dup := n.fields[key]; !dup {
+					n.keys = append(n.keys, key)
+				}
+				n.fields[key] = child
+			}
+		case '[':
+			n.kind = kindArray
+			for p.dec.More() {
+				child, err := p.value()
+				if err != nil {
+					return nil, err
+				}
+				n.items = append(n.items, child)
+			}
+		}
+		if _, err := p.dec.Token(); err != nil {
+			return nil, p.syntaxError(err)
+		}
+	case json.Number:
+		f, _ := t.Float64()
+		n.value = f
+	default:
+		n.value = t
+	}
+	return n, nil
+}
+
+var yamlErrorLine = regexp.MustCompile(`line (\d+)`)
+
+// parseYAML decodes a YAML document into a node tree.
+func parseYAML(content []byte) (*node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		perr := &ParseError{Message: err.Error()}
+		if m := yamlErrorLine.FindStringSubmatch(err.Error()); m != nil {
+			perr.Line, _ = strconv.Atoi(m[1])
+		}
+		return nil, perr
+	}
+	if len(doc.Content) == 0 {
+		return &node{kind: kindScalar, line: 1, column: 1}, nil
+	}
+	return fromYAML(doc.Content[0])
+}
+
+func fromYAML(y *yaml.Node) (*node, error) {
+	for y.Kind == yaml.AliasNode && y.Alias != nil {
+		y = y.Alias
+	}
+	n := &node{line: y.Line, column: y.Column}
+	switch y.Kind {
+	case yaml.MappingNode:
+		n.kind = kindObject
+		n.fields = make(map[string]*node)
+		for i := 0; i+1 < len(y.Content); i += 2 {
+			key := y.Content[i].Value
+			child, err := fromYAML(y.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			if _, dup := n.fields[key]; !dup {
+				n.keys = append(n.keys, key)
+			}
+			n.fields[key] = child
+		}
+	case yaml.SequenceNode:
+		n.kind = kindArray
+		for _, item := range y.Content {
+			child, err := fromYAML(item)
+			if err != nil {
+				return nil, err
+			}
+			n.items = append(n.items, child)
+		}
+	default:
+		var v any
+		if err := y.Decode(&v); err != nil {
+			return nil, &ParseError{Line: y.Line, Column: y.Column, Message: err.Error()}
+		}
+		n.value = normalizeScalar(v)
+	}
+	return n, nil
+}
+
+// normalizeScalar maps YAML numbers onto float64 so they compare equal to
+// the JSON numbers used by schemas.
+func normalizeScalar(v any) any {
+	switch t := v.(type) {
+	case int:
+		return float64(t)
+	case int64:
+		return float64(t)
+	case uint64:
+		return float64(t)
+	case float32:
+		return float64(t)
+	}
+	return v
+}
+
+// typeName returns the JSON Schema type of the node.
+func (n *node) typeName() string {
+	switch n.kind {
+	case kindObject:
+		return "object"
+	case kindArray:
+		return "array"
+	}
+	switch v := n.value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == float64(int64(v)) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	}
+	return "string"
+}
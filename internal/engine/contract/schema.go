@@ -0,0 +1,180 @@
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Schema is a JSON Schema document. Only the structural subset used for
+// output contracts is enforced: type, enum, const, required, properties,
+// additionalProperties, items, minItems, maxItems, minLength, maxLength,
+// pattern, minimum and maximum. Other keywords are ignored.
+type Schema map[string]any
+
+// LoadSchema reads a JSON Schema from a .json, .yaml or .yml file.
+func LoadSchema(path string) (Schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var schema Schema
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		var raw map[string]any
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("parsing schema %s: %w", path, err)
+		}
+		// Round-trip through JSON so numbers and nested maps match JSON decoding.
+		encoded, err := json.Marshal(raw)
+		if err != nil {
+			return nil, fmt.Errorf("parsing schema %s: %w", path, err)
+		}
+		data = encoded
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("parsing schema %s: %w", path, err)
+	}
+	return schema, nil
+}
+
+// validate appends a violation for every way n fails to match the schema.
+func (s Schema) validate(n *node, field string, report func(n *node, field, format string, args ...any)) {
+	if len(s) == 0 {
+		return
+	}
+
+	if types := schemaTypes(s["type"]); len(types) > 0 {
+		actual := n.typeName()
+		ok := false
+		for _, t := range types {
+			if t == actual || (t == "number" && actual == "integer") {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			report(n, field, "expected %s, got %s", strings.Join(types, " or "), actual)
+			return
+		}
+	}
+
+	if enum, ok := s["enum"].([]any); ok && n.kind == kindScalar {
+		found := false
+		for _, v := range enum {
+			if reflect.DeepEqual(v, n.value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			report(n, field, "value %v is not one of %v", n.value, enum)
+		}
+	}
+	if c, ok := s["const"]; ok && n.kind == kindScalar && !reflect.DeepEqual(c, n.value) {
+		report(n, field, "value %v must be %v", n.value, c)
+	}
+
+	switch n.kind {
+	case kindObject:
+		if required, ok := s["required"].([]any); ok {
+			for _, r := range required {
+				name, _ := r.(string)
+				if _, present := n.fields[name]; !present {
+					report(n, field, "missing required property %q", name)
+				}
+			}
+		}
+		properties, _ := s["properties"].(map[string]any)
+		for _, key := range n.keys {
+			child := n.fields[key]
+			if prop, ok := properties[key].(map[string]any); ok {
+				Schema(prop).validate(child, joinField(field, key), report)
+				continue
+			}
+			switch extra := s["additionalProperties"].(type) {
+			case bool:
+				if !extra {
+					report(child, joinField(field, key), "property %q is not allowed", key)
+				}
+			case map[string]any:
+				Schema(extra).validate(child, joinField(field, key), report)
+			}
+		}
+	case kindArray:
+		if min, ok := schemaInt(s["minItems"]); ok && len(n.items) < min {
+			report(n, field, "expected at least %d items, got %d", min, len(n.items))
+		}
+		if max, ok := schemaInt(s["maxItems"]); ok && len(n.items) > max {
+			report(n, field, "expected at most %d items, got %d", max, len(n.items))
+		}
+		if items, ok := s["items"].(map[string]any); ok {
+			for i, item := range n.items {
+				Schema(items).validate(item, fmt.Sprintf("%s[%d]", field, i), report)
+			}
+		}
+	default:
+		switch v := n.value.(type) {
+		case string:
+			length := utf8.RuneCountInString(v)
+			if min, ok := schemaInt(s["minLength"]); ok && length < min {
+				report(n, field, "expected at least %d characters, got %d", min, length)
+			}
+			if max, ok := schemaInt(s["maxLength"]); ok && length > max {
+				report(n, field, "expected at most %d characters, got %d", max, length)
+			}
+			if pattern, ok := s["pattern"].(string); ok {
+				re, err := regexp.Compile(pattern)
+				if err != nil {
+					report(n, field, "invalid schema pattern %q: %v", pattern, err)
+				} else if !re.MatchString(v) {
+					report(n, field, "value %q does not match pattern %q", v, pattern)
+				}
+			}
+		case float64:
+			if min, ok := s["minimum"].(float64); ok && v < min {
+				report(n, field, "value %v is less than minimum %v", v, min)
+			}
+			if max, ok := s["maximum"].(float64); ok && v > max {
+				report(n, field, "value %v is greater than maximum %v", v, max)
+			}
+		}
+	}
+}
+
+func schemaTypes(v any) []string {
+	switch t := v.(type) {
+	case string:
+		return []string{t}
+	case []any:
+		types := make([]string, 0, len(t))
+		for _, item := range t {
+			if s, ok := item.(string); ok {
+				types = append(types, s)
+			}
+		}
+		sort.Strings(types)
+		return types
+	}
+	return nil
+}
+
+func schemaInt(v any) (int, bool) {
+	f, ok := v.(float64)
+	return int(f), ok
+}
+
+func joinField(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
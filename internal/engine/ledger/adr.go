@@ -0,0 +1,182 @@
+package ledger
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"specfirst/internal/engine/markdown"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ADR is an architecture decision record.
+type ADR struct {
+	Title        string
+	Status       string    // ADR status, e.g. accepted or superseded
+	Date         time.Time // zero when the record has no date
+	Rationale    string
+	Alternatives []string
+}
+
+// ADRStatus returns the ADR status of a decision: reversed decisions are
+// superseded.
+func ADRStatus(decisionStatus string) string {
+	if decisionStatus == "reversed" {
+		return "superseded"
+	}
+	return decisionStatus
+}
+
+// DecisionStatus maps an ADR status back to a decision status. Superseded,
+// deprecated and rejected records are reversed; anything other than accepted
+// is proposed.
+func DecisionStatus(adrStatus string) string {
+	status := strings.ToLower(strings.TrimSpace(adrStatus))
+	switch {
+	case strings.HasPrefix(status, "accepted"):
+		return "accepted"
+	case strings.HasPrefix(status, "superseded"), strings.HasPrefix(status, "deprecated"), strings.HasPrefix(status, "rejected"):
+		return "reversed"
+	}
+	return "proposed"
+}
+
+var (
+	adrFileName = regexp.MustCompile(`^(\d{4})-[^/\\]*\.md$`)
+	slugInvalid = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// ADRNumber returns the number of an ADR file named NNNN-title.md.
+func ADRNumber(name string) (int, bool) {
+	m := adrFileName.FindStringSubmatch(name)
+	if m == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(m[1])
+	return n, err == nil
+}
+
+// ADRFileName returns the NNNN-title.md file name of an ADR.
+func ADRFileName(number int, title string) string {
+	slug := strings.Trim(slugInvalid.ReplaceAllString(strings.ToLower(title), "-"), "-")
+	if len(slug) > 60 {
+		slug = strings.TrimRight(slug[:60], "-")
+	}
+	if slug == "" {
+		slug = "decision"
+	}
+	return fmt.Sprintf("%04d-%s.md", number, slug)
+}
+
+// RenderADR writes an ADR in MADR format: status and date in front matter,
+// the decision as title and chosen option, alternatives as the other
+// considered options and the rationale as its justification.
+func RenderADR(adr ADR) string {
+	var b strings.Builder
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "status: %s\n", adr.Status)
+	if !adr.Date.IsZero() {
+		fmt.Fprintf(&b, "date: %s\n", adr.Date.Format("2006-01-02"))
+	}
+	b.WriteString("---\n\n")
+	fmt.Fprintf(&b, "# %s\n", adr.Title)
+	if len(adr.Alternatives) > 0 {
+		b.WriteString("\n## Considered Options\n\n")
+		fmt.Fprintf(&b, "* %s\n", adr.Title)
+		for _, alt := range adr.Alternatives {
+			fmt.Fprintf(&b, "* %s\n", alt)
+		}
+	}
+	b.WriteString("\n## Decision Outcome\n\n")
+	if rationale := strings.TrimSpace(adr.Rationale); rationale != "" {
+		fmt.Fprintf(&b, "Chosen option: %q, because %s\n", adr.Title, rationale)
+	} else {
+		fmt.Fprintf(&b, "Chosen option: %q.\n", adr.Title)
+	}
+	return b.String()
+}
+
+var (
+	adrTitlePrefix = regexp.MustCompile(`^(?:ADR[-\s]?\d+\s*[:.\-]?|\d+\.)\s*`)
+	statusLine     = regexp.MustCompile(`(?im)^\s*[-*]?\s*status:\s*(.+)$`)
+	dateLine       = regexp.MustCompile(`(?im)^\s*[-*]?\s*date:\s*(\d{4}-\d{2}-\d{2})`)
+	chosenOption   = regexp.MustCompile(`(?s)^Chosen option:\s*"((?:[^"\\]|\\.)*)"\s*(?:,\s*because\s*|\.\s*)?`)
+)
+
+// ParseADR reads an ADR in MADR format (front matter, or the "* Status:"
+// lines of older MADR versions) or in Nygard's format (Status, Context and
+// Decision sections). The title drops numbering such as "ADR-0003:" or "3.".
+func ParseADR(content string) ADR {
+	outline := markdown.Parse(content)
+	var adr ADR
+
+	var front struct {
+		Status string `yaml:"status"`
+		Date   string `yaml:"date"`
+	}
+	if preamble := strings.TrimSpace(outline.Preamble); strings.HasPrefix(preamble, "---") {
+		block := strings.TrimPrefix(preamble, "---")
+		if end := strings.Index(block, "\n---"); end >= 0 {
+			_ = yaml.Unmarshal([]byte(block[:end]), &front)
+		}
+	}
+
+	var title *markdown.Section
+	for _, s := range outline.Sections {
+		if s.Level == 1 {
+			title = s
+			break
+		}
+	}
+	if title == nil {
+		return adr
+	}
+	adr.Title = strings.TrimSpace(adrTitlePrefix.ReplaceAllString(title.Title, ""))
+
+	adr.Status = front.Status
+	if s := outline.Find([]string{"Status"}); adr.Status == "" && s != nil {
+		if fields := strings.Fields(s.Body); len(fields) > 0 {
+			adr.Status = strings.Trim(fields[0], ".,")
+		}
+	}
+	if m := statusLine.FindStringSubmatch(title.Body); adr.Status == "" && m != nil {
+		adr.Status = strings.TrimSpace(m[1])
+	}
+	adr.Status = strings.ToLower(adr.Status)
+
+	date := front.Date
+	if m := dateLine.FindStringSubmatch(title.Body); date == "" && m != nil {
+		date = m[1]
+	}
+	if t, err := time.Parse("2006-01-02", strings.TrimSpace(date)); err == nil {
+		adr.Date = t
+	}
+
+	chosen := adr.Title
+	outcome := outline.Find([]string{"Decision Outcome"})
+	if outcome == nil {
+		outcome = outline.Find([]string{"Decision"})
+	}
+	if outcome != nil {
+		body := strings.TrimSpace(outcome.Body)
+		if m := chosenOption.FindStringSubmatch(body); m != nil {
+			if option, err := strconv.Unquote(`"` + m[1] + `"`); err == nil {
+				chosen = option
+			}
+			body = body[len(m[0]):]
+		}
+		adr.Rationale = strings.TrimSpace(body)
+	}
+
+	if options := outline.Find([]string{"Considered Options"}); options != nil {
+		for _, item := range options.Items() {
+			if Normalize(item.Text) != Normalize(chosen) && Normalize(item.Text) != Normalize(adr.Title) {
+				adr.Alternatives = append(adr.Alternatives, item.Text)
+			}
+		}
+	}
+	return adr
+}
@@ -0,0 +1,53 @@
+package ledger
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRenderAndParseADRRoundTrip(t *testing.T) {
+	adr := ADR{
+		Title:        `Use "Postgres" for storage`,
+		Status:       "superseded",
+		Date:         time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC),
+		Rationale:    "it supports JSON columns.",
+		Alternatives: []string{"MySQL", "SQLite"},
+	}
+	content := RenderADR(adr)
+	want := "---\nstatus: superseded\ndate: 2026-03-04\n---\n\n# Use \"Postgres\" for storage\n\n## Considered Options\n\n" +
+		"* Use \"Postgres\" for storage\n* MySQL\n* SQLite\n\n## Decision Outcome\n\n" +
+		"Chosen option: \"Use \\\"Postgres\\\" for storage\", because it supports JSON columns.\n"
+	if content != want {
+		t.Fatalf("unexpected render:\n%s", content)
+	}
+	if got := ParseADR(content); !reflect.DeepEqual(got, adr) {
+		t.Fatalf("round trip mismatch:\n got %+v\nwant %+v", got, adr)
+	}
+	if status := DecisionStatus(adr.Status); status != "reversed" {
+		t.Fatalf("superseded should map to reversed, got %s", status)
+	}
+}
+
+func TestParseADRFormats(t *testing.T) {
+	nygard := "# 3. Record architecture decisions\n\nDate: 2024-01-02\n\n## Status\n\nAccepted\n\n## Context\n\nWe need a log.\n\n## Decision\n\nWe will use ADRs.\n"
+	adr := ParseADR(nygard)
+	if adr.Title != "Record architecture decisions" || adr.Status != "accepted" || adr.Rationale != "We will use ADRs." || adr.Date.Format("2006-01-02") != "2024-01-02" {
+		t.Fatalf("unexpected nygard ADR: %+v", adr)
+	}
+
+	madr2 := "# ADR-0007: Queue writes\n\n* Status: superseded by ADR-0009\n* Date: 2025-05-06\n\n## Considered Options\n\n* Queue writes\n* Synchronous writes\n\n## Decision Outcome\n\nChosen option: \"Queue writes\", because bursts.\n"
+	adr = ParseADR(madr2)
+	if adr.Title != "Queue writes" || DecisionStatus(adr.Status) != "reversed" || adr.Rationale != "bursts." || !reflect.DeepEqual(adr.Alternatives, []string{"Synchronous writes"}) {
+		t.Fatalf("unexpected MADR 2 ADR: %+v", adr)
+	}
+
+	for name, want := range map[string]int{"0012-use-go.md": 12, "template.md": 0, "12-short.md": 0} {
+		if n, _ := ADRNumber(name); n != want {
+			t.Errorf("ADRNumber(%q) = %d, want %d", name, n, want)
+		}
+	}
+	if name := ADRFileName(4, "Use Postgres (v16) for storage!"); name != "0004-use-postgres-v16-for-storage.md" {
+		t.Fatalf("unexpected file name %s", name)
+	}
+}
@@ -0,0 +1,211 @@
+// Package ledger extracts epistemic ledger entries (assumptions, open
+// questions, risks and decisions) from stage artifacts.
+package ledger
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"specfirst/internal/domain"
+	"specfirst/internal/engine/markdown"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Entry is a ledger entry found in an artifact.
+type Entry struct {
+	Kind string // one of the domain.Kind* constants
+	Text string
+	Line int // 1-based line in the artifact
+
+	Owner        string   // assumptions
+	Tags         []string // questions
+	Severity     string   // risks: low, medium or high (empty when not stated)
+	Mitigation   string   // risks
+	Rationale    string   // decisions
+	Alternatives []string // decisions
+}
+
+// Extract returns the entries in an artifact, ordered by line. Markdown
+// artifacts contribute the list items under headings whose title mentions
+// assumptions, questions, risks or decisions, and the items of fenced YAML
+// blocks with assumptions, open_questions (or questions), risks or decisions
+// keys. YAML artifacts are read as a single such block.
+func Extract(name, content string) []Entry {
+	var entries []Entry
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml":
+		entries = extractYAML(content, 1)
+	default:
+		outline := markdown.Parse(content)
+		entries = extractSections(outline.Sections, "")
+		for _, block := range markdown.CodeBlocks(content) {
+			if info := strings.Fields(block.Info); len(info) > 0 && (info[0] == "yaml" || info[0] == "yml") {
+				entries = append(entries, extractYAML(block.Content, block.Line)...)
+			}
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Line < entries[j].Line })
+	return entries
+}
+
+// extractSections walks the outline; subsections without a kind of their own
+// inherit their parent's.
+func extractSections(sections []*markdown.Section, inherited string) []Entry {
+	var entries []Entry
+	for _, s := range sections {
+		kind := sectionKind(s.Title)
+		if kind == "" {
+			kind = inherited
+		}
+		if kind != "" {
+			for _, item := range s.Items() {
+				if entry, ok := itemEntry(kind, item); ok {
+					entries = append(entries, entry)
+				}
+			}
+		}
+		entries = append(entries, extractSections(s.Children, kind)...)
+	}
+	return entries
+}
+
+func sectionKind(title string) string {
+	t := strings.ToLower(title)
+	switch {
+	case strings.Contains(t, "assumption"):
+		return domain.KindAssumption
+	case strings.Contains(t, "question"):
+		return domain.KindQuestion
+	case strings.Contains(t, "risk"):
+		return domain.KindRisk
+	case strings.Contains(t, "decision"):
+		return domain.KindDecision
+	}
+	return ""
+}
+
+var (
+	checkbox        = regexp.MustCompile(`^\[[ xX]\]\s+`)
+	severityTag     = regexp.MustCompile(`(?i)\s*[\[(]\s*(?:severity:\s*)?(low|medium|high)\s*[\])]\s*`)
+	severityPrefix  = regexp.MustCompile(`(?i)^(?:\*\*)?(low|medium|high)(?:\*\*)?\s*[:\-–]\s*`)
+	severityInline  = regexp.MustCompile(`(?i)\bseverity:\s*(low|medium|high)\b[.,;]?\s*`)
+	placeholderText = map[string]bool{"none": true, "n/a": true, "na": true, "-": true, "none identified": true, "none so far": true, "tbd": true}
+)
+
+func itemEntry(kind string, item markdown.Item) (Entry, bool) {
+	text := checkbox.ReplaceAllString(item.Text, "")
+	entry := Entry{Kind: kind, Line: item.Line}
+	if kind == domain.KindRisk {
+		for _, re := range []*regexp.Regexp{severityTag, severityPrefix, severityInline} {
+			if m := re.FindStringSubmatchIndex(text); m != nil {
+				entry.Severity = strings.ToLower(text[m[2]:m[3]])
+				text = strings.TrimSpace(text[:m[0]] + " " + text[m[1]:])
+				break
+			}
+		}
+	}
+	entry.Text = strings.TrimSpace(text)
+	if entry.Text == "" || placeholderText[Normalize(entry.Text)] {
+		return Entry{}, false
+	}
+	return entry, true
+}
+
+// yamlKinds maps the keys of a ledger YAML block to entry kinds.
+var yamlKinds = map[string]string{
+	"assumptions":    domain.KindAssumption,
+	"open_questions": domain.KindQuestion,
+	"questions":      domain.KindQuestion,
+	"risks":          domain.KindRisk,
+	"decisions":      domain.KindDecision,
+}
+
+// yamlItem is a ledger item written as a mapping. The text may be given under
+// "text" or under the singular of its kind.
+type yamlItem struct {
+	Text         string   `yaml:"text"`
+	Assumption   string   `yaml:"assumption"`
+	Question     string   `yaml:"question"`
+	Risk         string   `yaml:"risk"`
+	Decision     string   `yaml:"decision"`
+	Owner        string   `yaml:"owner"`
+	Tags         []string `yaml:"tags"`
+	Severity     string   `yaml:"severity"`
+	Mitigation   string   `yaml:"mitigation"`
+	Rationale    string   `yaml:"rationale"`
+	Alternatives []string `yaml:"alternatives"`
+}
+
+// extractYAML reads a ledger block; firstLine is the artifact line of the
+// block's first line. Blocks that do not parse or have no ledger keys yield
+// nothing.
+func extractYAML(content string, firstLine int) []Entry {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &doc); err != nil || len(doc.Content) == 0 {
+		return nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil
+	}
+	var entries []Entry
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		kind, ok := yamlKinds[strings.ToLower(root.Content[i].Value)]
+		list := root.Content[i+1]
+		if !ok || list.Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, node := range list.Content {
+			entry := Entry{Kind: kind, Line: firstLine + node.Line - 1}
+			switch node.Kind {
+			case yaml.ScalarNode:
+				entry.Text = node.Value
+			case yaml.MappingNode:
+				var item yamlItem
+				if err := node.Decode(&item); err != nil {
+					continue
+				}
+				entry.Text = firstNonEmpty(item.Text, item.Assumption, item.Question, item.Risk, item.Decision)
+				entry.Owner, entry.Tags = item.Owner, item.Tags
+				entry.Severity, entry.Mitigation = riskSeverity(item.Severity), item.Mitigation
+				entry.Rationale, entry.Alternatives = item.Rationale, item.Alternatives
+			default:
+				continue
+			}
+			entry.Text = strings.TrimSpace(entry.Text)
+			if entry.Text == "" || placeholderText[Normalize(entry.Text)] {
+				continue
+			}
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// riskSeverity returns low, medium or high, or "" for anything else.
+func riskSeverity(s string) string {
+	switch s = strings.ToLower(strings.TrimSpace(s)); s {
+	case "low", "medium", "high":
+		return s
+	}
+	return ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Normalize returns the form of an entry's text used to detect duplicates:
+// lower case, single spaces and no trailing punctuation.
+func Normalize(text string) string {
+	text = strings.ToLower(strings.Join(strings.Fields(text), " "))
+	return strings.TrimRight(text, ".?!:;, ")
+}
@@ -0,0 +1,70 @@
+package ledger
+
+import (
+	"reflect"
+	"testing"
+
+	"specfirst/internal/domain"
+)
+
+const designArtifact = `# Design
+
+## Assumptions
+- Users sign in with SSO.
+- [x] Traffic stays under
+  1k requests per second.
+- None
+
+## Risks
+- (high) Vendor API rate limits
+- Low: schema drift
+  - nested detail
+
+### Open Questions
+1. Which region hosts the data?
+
+` + "```yaml" + `
+decisions:
+  - Use Postgres
+  - text: Queue writes
+    rationale: smooth bursts
+    alternatives: [sync writes]
+risks:
+  - risk: Cost overrun
+    severity: critical
+` + "```" + `
+
+## Risk Notes
+Plain paragraph, not a list.
+`
+
+func TestExtractMarkdownSectionsAndYAML(t *testing.T) {
+	got := Extract("design.md", designArtifact)
+	want := []Entry{
+		{Kind: domain.KindAssumption, Text: "Users sign in with SSO.", Line: 4},
+		{Kind: domain.KindAssumption, Text: "Traffic stays under 1k requests per second.", Line: 5},
+		{Kind: domain.KindRisk, Text: "Vendor API rate limits", Line: 10, Severity: "high"},
+		{Kind: domain.KindRisk, Text: "schema drift nested detail", Line: 11, Severity: "low"},
+		{Kind: domain.KindQuestion, Text: "Which region hosts the data?", Line: 15},
+		{Kind: domain.KindDecision, Text: "Use Postgres", Line: 19},
+		{Kind: domain.KindDecision, Text: "Queue writes", Line: 20, Rationale: "smooth bursts", Alternatives: []string{"sync writes"}},
+		{Kind: domain.KindRisk, Text: "Cost overrun", Line: 24},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Extract:\n got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestExtractYAMLArtifact(t *testing.T) {
+	got := Extract("ledger.yaml", "open_questions:\n  - question: Who owns billing?\n    tags: [billing]\n")
+	want := []Entry{{Kind: domain.KindQuestion, Text: "Who owns billing?", Line: 2, Tags: []string{"billing"}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Extract = %+v, want %+v", got, want)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	if Normalize("  Use   Postgres. ") != Normalize("use postgres") {
+		t.Fatal("expected case, spacing and trailing punctuation to be ignored")
+	}
+}
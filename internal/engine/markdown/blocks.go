@@ -0,0 +1,81 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Item is a list item. Continuation lines and nested items are folded into
+// its text.
+type Item struct {
+	Text string
+	Line int // 1-based line of the list marker
+}
+
+// CodeBlock is a fenced code block.
+type CodeBlock struct {
+	Info    string // the info string after the opening fence, e.g. "yaml"
+	Line    int    // 1-based line of the first content line
+	Content string
+}
+
+var listMarker = regexp.MustCompile(`^ ?(?:[-*+]|\d{1,9}[.)])(?:[ \t]+|$)`)
+
+// Items returns the top-level list items (indented at most one space) of the
+// section's body, before its first subsection. Lines inside fenced code
+// blocks are ignored.
+func (s *Section) Items() []Item {
+	var items []Item
+	var fence string
+	for i, line := range strings.Split(s.Body, "\n") {
+		if marker := fenceMarker(line); marker != "" {
+			if fence == "" {
+				fence = marker
+			} else if marker[0] == fence[0] && len(marker) >= len(fence) && strings.TrimSpace(line) == marker {
+				fence = ""
+			}
+			continue
+		}
+		if fence != "" {
+			continue
+		}
+		if loc := listMarker.FindStringIndex(line); loc != nil {
+			items = append(items, Item{Text: strings.TrimSpace(line[loc[1]:]), Line: s.Line + 1 + i})
+			continue
+		}
+		// Continuation lines and nested items belong to the previous item.
+		indented := strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
+		if strings.TrimSpace(line) == "" || len(items) == 0 || !indented {
+			continue
+		}
+		last := &items[len(items)-1]
+		text := listMarker.ReplaceAllString(strings.TrimLeft(line, " \t"), "")
+		last.Text = strings.TrimSpace(last.Text + " " + strings.TrimSpace(text))
+	}
+	return items
+}
+
+// CodeBlocks returns the fenced code blocks of a document.
+func CodeBlocks(content string) []CodeBlock {
+	var blocks []CodeBlock
+	var fence string
+	var current *CodeBlock
+	var body []string
+	for i, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		marker := fenceMarker(line)
+		switch {
+		case fence == "" && marker != "":
+			fence = marker
+			info := strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), marker[:1]))
+			current = &CodeBlock{Info: info, Line: i + 2}
+			body = body[:0]
+		case fence != "" && marker != "" && marker[0] == fence[0] && len(marker) >= len(fence) && strings.TrimSpace(line) == marker:
+			current.Content = strings.Join(body, "\n")
+			blocks = append(blocks, *current)
+			fence, current = "", nil
+		case fence != "":
+			body = append(body, line)
+		}
+	}
+	return blocks
+}
@@ -0,0 +1,181 @@
+// Package markdown parses the heading outline of markdown documents.
+package markdown
+
+import (
+	"strings"
+)
+
+// Section is a heading and the content beneath it, up to the next heading of
+// the same or a higher level.
+type Section struct {
+	Title    string
+	Level    int    // 1 for "#", 2 for "##", ...
+	Line     int    // 1-based line of the heading
+	Body     string // text before the first subsection
+	Children []*Section
+
+	content string
+}
+
+// Content returns the section's text including its subsections, without its
+// own heading.
+func (s *Section) Content() string {
+	return s.content
+}
+
+// Outline is the heading tree of a document. Sections holds the top-level
+// headings; text before the first heading is kept in Preamble.
+type Outline struct {
+	Preamble string
+	Sections []*Section
+}
+
+// Parse builds the outline of a markdown document. Only ATX headings ("# Title")
+// are recognized, and lines inside fenced code blocks are never headings.
+func Parse(content string) Outline {
+	var outline Outline
+	var stack []*Section
+	var body []string
+	var fence string
+
+	flush := func() {
+		text := strings.Join(body, "\n")
+		body = body[:0]
+		if len(stack) == 0 {
+			outline.Preamble = text
+			return
+		}
+		stack[len(stack)-1].Body = text
+	}
+
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		if marker := fenceMarker(line); marker != "" {
+			switch {
+			case fence == "":
+				fence = marker
+			case marker[0] == fence[0] && len(marker) >= len(fence) && strings.TrimSpace(line) == marker:
+				fence = ""
+			}
+			body = append(body, line)
+			continue
+		}
+		if fence != "" {
+			body = append(body, line)
+			continue
+		}
+
+		level, title, ok := heading(line)
+		if !ok {
+			body = append(body, line)
+			continue
+		}
+		flush()
+		section := &Section{Title: title, Level: level, Line: i + 1}
+		for len(stack) > 0 && stack[len(stack)-1].Level >= level {
+			closeSection(stack[len(stack)-1], lines, i)
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			outline.Sections = append(outline.Sections, section)
+		} else {
+			parent := stack[len(stack)-1]
+			parent.Children = append(parent.Children, section)
+		}
+		stack = append(stack, section)
+	}
+	flush()
+	for _, open := range stack {
+		closeSection(open, lines, len(lines))
+	}
+	return outline
+}
+
+// closeSection records the section's content, which ends before line index end.
+func closeSection(s *Section, lines []string, end int) {
+	s.content = strings.Join(lines[s.Line:end], "\n")
+}
+
+// Find returns the section at a heading path such as ["Design", "Data Model"].
+// Titles match case-insensitively; each element may be nested at any depth
+// below the previous one. The first element may be at any level.
+func (o Outline) Find(path []string) *Section {
+	if len(path) == 0 {
+		return nil
+	}
+	return findIn(o.Sections, path)
+}
+
+func findIn(sections []*Section, path []string) *Section {
+	for _, s := range sections {
+		if strings.EqualFold(s.Title, strings.TrimSpace(path[0])) {
+			if len(path) == 1 {
+				return s
+			}
+			if found := findIn(s.Children, path[1:]); found != nil {
+				return found
+			}
+		}
+		if found := findIn(s.Children, path); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// SplitPath splits a section path written as "Design > Data Model".
+func SplitPath(path string) []string {
+	parts := strings.Split(path, ">")
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// heading parses an ATX heading line.
+func heading(line string) (int, string, bool) {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return 0, "", false // indented code
+	}
+	level := 0
+	for level < len(trimmed) && trimmed[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 {
+		return 0, "", false
+	}
+	rest := trimmed[level:]
+	if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+		return 0, "", false
+	}
+	title := strings.TrimSpace(rest)
+	// Drop an optional closing sequence of '#'s.
+	if stripped := strings.TrimRight(title, "#"); stripped != title && (stripped == "" || strings.HasSuffix(stripped, " ")) {
+		title = strings.TrimSpace(stripped)
+	}
+	return level, title, true
+}
+
+// fenceMarker returns the ``` or ~~~ run that opens or closes a code fence.
+func fenceMarker(line string) string {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 || len(trimmed) < 3 {
+		return ""
+	}
+	ch := trimmed[0]
+	if ch != '`' && ch != '~' {
+		return ""
+	}
+	n := 0
+	for n < len(trimmed) && trimmed[n] == ch {
+		n++
+	}
+	if n < 3 {
+		return ""
+	}
+	return trimmed[:n]
+}
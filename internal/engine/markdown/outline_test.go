@@ -0,0 +1,58 @@
+package markdown
+
+import (
+	"reflect"
+	"testing"
+
+	"specfirst/internal/domain"
+)
+
+const designDoc = "# Design\n\nOverview.\n\n## Data Model\n\nUsers and orders.\n\n```md\n# Not A Heading\n```\n\n### Indexes\n\n## API\n\n# Risks ##\n"
+
+func TestParseBuildsHeadingTree(t *testing.T) {
+	outline := Parse(designDoc)
+	if len(outline.Sections) != 2 {
+		t.Fatalf("expected 2 top-level sections, got %d", len(outline.Sections))
+	}
+	design := outline.Sections[0]
+	var children []string
+	for _, c := range design.Children {
+		children = append(children, c.Title)
+	}
+	if !reflect.DeepEqual(children, []string{"Data Model", "API"}) {
+		t.Fatalf("Design children = %v", children)
+	}
+	if outline.Sections[1].Title != "Risks" || outline.Sections[1].Line != 17 {
+		t.Fatalf("unexpected closing section: %+v", outline.Sections[1])
+	}
+	if outline.Find([]string{"Not A Heading"}) != nil {
+		t.Fatal("headings inside fenced code must be ignored")
+	}
+	if s := outline.Find(SplitPath("design > indexes")); s == nil || s.Level != 3 {
+		t.Fatalf("expected nested path to match Indexes, got %+v", s)
+	}
+	if outline.Find(SplitPath("Risks > Indexes")) != nil {
+		t.Fatal("path must respect nesting")
+	}
+}
+
+func TestCheckSections(t *testing.T) {
+	rules := []domain.SectionRule{
+		{Path: "Design > Data Model", MinLength: 10},
+		{Path: "Design > API", NonEmpty: true},
+		{Path: "Design > Data Model > Indexes", MinLength: 100},
+		{Path: "Rollout"},
+	}
+	var got []string
+	for _, p := range CheckSections(designDoc, rules) {
+		got = append(got, p.Message)
+	}
+	want := []string{
+		`section "Design > API" is empty`,
+		`section "Design > Data Model > Indexes" has 0 characters (min 100)`,
+		`missing section "Rollout"`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("problems = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,41 @@
+package markdown
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"specfirst/internal/domain"
+)
+
+// Problem is a section rule a document does not satisfy. Line is the heading
+// of the offending section, or 0 when the section is missing.
+type Problem struct {
+	Path    string
+	Line    int
+	Message string
+}
+
+// CheckSections returns a problem for every rule the document violates.
+func CheckSections(content string, rules []domain.SectionRule) []Problem {
+	if len(rules) == 0 {
+		return nil
+	}
+	outline := Parse(content)
+	var problems []Problem
+	for _, rule := range rules {
+		section := outline.Find(SplitPath(rule.Path))
+		if section == nil {
+			problems = append(problems, Problem{Path: rule.Path, Message: fmt.Sprintf("missing section %q", rule.Path)})
+			continue
+		}
+		length := utf8.RuneCountInString(strings.TrimSpace(section.Content()))
+		switch {
+		case rule.NonEmpty && length == 0:
+			problems = append(problems, Problem{Path: rule.Path, Line: section.Line, Message: fmt.Sprintf("section %q is empty", rule.Path)})
+		case rule.MinLength > 0 && length < rule.MinLength:
+			problems = append(problems, Problem{Path: rule.Path, Line: section.Line, Message: fmt.Sprintf("section %q has %d characters (min %d)", rule.Path, length, rule.MinLength)})
+		}
+	}
+	return problems
+}
@@ -2,29 +2,77 @@ package prompt
 
 import (
 	"regexp"
+	"sort"
 	"strings"
 
 	"specfirst/internal/domain"
+	"specfirst/internal/engine/markdown"
 )
 
-// Schema defines validation rules for generated prompts.
+// Schema defines validation rules for generated prompts and, through rules
+// scoped to artifacts, for stage artifacts.
 type Schema struct {
 	RequiredSections []string
 	ForbiddenPhrases []string
+	Rules            []Rule
 }
 
-// Merge adds rules from a LintConfig to the schema.
+// Rule is a compiled regex lint rule.
+type Rule struct {
+	domain.LintRule
+	re *regexp.Regexp
+}
+
+// Merge adds rules from a LintConfig to the schema. Rules with invalid
+// patterns are skipped; they are rejected when protocols and rule files load.
 func (s *Schema) Merge(cfg *domain.LintConfig) {
 	if cfg == nil {
 		return
 	}
 	s.RequiredSections = append(s.RequiredSections, cfg.RequiredSections...)
 	s.ForbiddenPhrases = append(s.ForbiddenPhrases, cfg.ForbiddenPhrases...)
+	for _, rule := range cfg.Rules {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			continue
+		}
+		s.Rules = append(s.Rules, Rule{LintRule: rule, re: re})
+	}
+}
+
+// ForStage returns the schema with only the rules that apply to stageID.
+func (s Schema) ForStage(stageID string) Schema {
+	out := s
+	out.Rules = nil
+	for _, rule := range s.Rules {
+		if rule.AppliesTo(domain.LintScopePrompt, stageID) || rule.AppliesTo(domain.LintScopeArtifact, stageID) {
+			out.Rules = append(out.Rules, rule)
+		}
+	}
+	return out
+}
+
+// Rule IDs for the issues reported by Validate.
+const (
+	RuleMissingSection  = "missing-prompt-section"
+	RuleForbiddenPhrase = "forbidden-phrase"
+	RuleAmbiguity       = "prompt-ambiguity"
+)
+
+// Issue is a single lint warning and the rule that raised it. Line and Column
+// are set for regex rule matches (1-based).
+type Issue struct {
+	Rule    string
+	Message string
+	Line    int
+	Column  int
 }
 
-// ValidationResult holds lint warnings for a prompt.
+// ValidationResult holds lint warnings for a prompt. Issues carries the same
+// warnings tagged with their rule IDs.
 type ValidationResult struct {
 	Warnings []string
+	Issues   []Issue
 }
 
 // DefaultSchema returns the built-in prompt schema with sensible defaults.
@@ -47,28 +95,46 @@ func DefaultSchema() Schema {
 			"optimize this",
 			"make it perfect",
 		},
+		Rules: ambiguityRules,
 	}
 }
 
+// ambiguityRules are the built-in vague-language checks.
+var ambiguityRules = compileRules([]domain.LintRule{
+	{Pattern: `(?i)\b(maybe|perhaps|possibly|might)\b\s+.*\b(add|include|consider|use)\b`},
+	{Pattern: `(?i)\b(as needed|if necessary|when appropriate|where applicable|as appropriate)\b`},
+	{Pattern: `(?i)\b(etc\.?|and so on|and the like)\b`},
+	{Pattern: `(?i)\b(some|various|various different|multiple)\b\s+(things|stuff|items|parts|features)\b`},
+	{Pattern: `(?i)\b(in a way that|to be determined|tbd)\b`},
+	{Pattern: `(?i)\b(ensure|make sure)\b\s+.*\b(good|better|perfect|nice)\b`},
+})
+
+func compileRules(rules []domain.LintRule) []Rule {
+	compiled := make([]Rule, len(rules))
+	for i, rule := range rules {
+		rule.ID = RuleAmbiguity
+		rule.Message = "vague language detected"
+		rule.Severity = domain.LintWarning
+		compiled[i] = Rule{LintRule: rule, re: regexp.MustCompile(rule.Pattern)}
+	}
+	return compiled
+}
+
 // Validate checks a prompt against the schema and returns validation warnings.
 func Validate(prompt string, schema Schema) ValidationResult {
-	var warnings []string
+	var result ValidationResult
+	warn := func(rule, message string) {
+		result.Warnings = append(result.Warnings, message)
+		result.Issues = append(result.Issues, Issue{Rule: rule, Message: message})
+	}
 
-	// Check for required sections
+	// Check for required sections (headings at any level, outside code fences;
+	// "Parent > Child" requires nesting)
 	promptLower := strings.ToLower(prompt)
+	outline := markdown.Parse(prompt)
 	for _, section := range schema.RequiredSections {
-		// Look for section headers (# Section, ## Section, etc.) with flexible whitespace
-		pattern := "(?mi)^#+\\s+" + regexp.QuoteMeta(strings.TrimSpace(section)) + "\\s*$"
-		re, err := regexp.Compile(pattern)
-		if err != nil {
-			if !strings.Contains(strings.ToLower(prompt), strings.ToLower(section)) {
-				warnings = append(warnings, "missing required section: "+section)
-			}
-			continue
-		}
-
-		if !re.MatchString(prompt) {
-			warnings = append(warnings, "missing required section: "+section)
+		if outline.Find(markdown.SplitPath(section)) == nil {
+			warn(RuleMissingSection, "missing required section: "+section)
 		}
 	}
 
@@ -76,11 +142,81 @@ func Validate(prompt string, schema Schema) ValidationResult {
 	for _, phrase := range schema.ForbiddenPhrases {
 		phraseLower := strings.ToLower(phrase)
 		if strings.Contains(promptLower, phraseLower) {
-			warnings = append(warnings, "contains ambiguous phrase: \""+phrase+"\"")
+			warn(RuleForbiddenPhrase, "contains ambiguous phrase: \""+phrase+"\"")
 		}
 	}
 
-	return ValidationResult{Warnings: warnings}
+	// Regex rules report their first match
+	for _, rule := range schema.Rules {
+		if rule.Scope == domain.LintScopeArtifact {
+			continue
+		}
+		if loc := rule.re.FindStringIndex(prompt); loc != nil {
+			message := rule.Message + ": \"" + prompt[loc[0]:loc[1]] + "\""
+			result.Warnings = append(result.Warnings, message)
+			result.Issues = append(result.Issues, Issue{Rule: rule.ID, Message: message, Line: lineAt(prompt, loc[0]), Column: loc[0] - strings.LastIndex(prompt[:loc[0]], "\n")})
+		}
+	}
+
+	return result
+}
+
+// ValidateArtifact applies the schema's artifact-scoped rules to an artifact,
+// reporting every matching line.
+func ValidateArtifact(content string, schema Schema) []Issue {
+	var issues []Issue
+	for _, rule := range schema.Rules {
+		if rule.Scope == domain.LintScopeArtifact || rule.Scope == domain.LintScopeAll {
+			issues = append(issues, matchLines(content, rule)...)
+		}
+	}
+	return issues
+}
+
+// LintArtifact checks an artifact with the prompt vocabulary as well: the
+// forbidden phrases and the built-in ambiguity rules, plus the artifact-scoped
+// rules. Every matching line is reported.
+func LintArtifact(content string, schema Schema) []Issue {
+	var issues []Issue
+	for _, phrase := range schema.ForbiddenPhrases {
+		rule := Rule{
+			LintRule: domain.LintRule{ID: RuleForbiddenPhrase, Message: "contains ambiguous phrase"},
+			re:       regexp.MustCompile(`(?i)` + regexp.QuoteMeta(phrase)),
+		}
+		issues = append(issues, matchLines(content, rule)...)
+	}
+	for _, rule := range schema.Rules {
+		if rule.ID == RuleAmbiguity || rule.Scope == domain.LintScopeArtifact || rule.Scope == domain.LintScopeAll {
+			issues = append(issues, matchLines(content, rule)...)
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Line < issues[j].Line })
+	return issues
+}
+
+// matchLines reports the first match of a rule on each line.
+func matchLines(content string, rule Rule) []Issue {
+	var issues []Issue
+	lastLine := 0
+	for _, loc := range rule.re.FindAllStringIndex(content, -1) {
+		line := lineAt(content, loc[0])
+		if line == lastLine {
+			continue
+		}
+		lastLine = line
+		issues = append(issues, Issue{
+			Rule:    rule.ID,
+			Message: rule.Message + ": \"" + content[loc[0]:loc[1]] + "\"",
+			Line:    line,
+			Column:  loc[0] - strings.LastIndex(content[:loc[0]], "\n"),
+		})
+	}
+	return issues
+}
+
+// lineAt returns the 1-based line of a byte offset.
+func lineAt(text string, offset int) int {
+	return strings.Count(text[:offset], "\n") + 1
 }
 
 // ValidateStructure checks if a prompt has proper structure for its stage type.
@@ -112,22 +248,10 @@ func ValidateStructure(prompt string, stageType string) ValidationResult {
 // ContainsAmbiguity checks if the prompt contains ambiguous language.
 func ContainsAmbiguity(prompt string) []string {
 	var issues []string
-
-	vaguePatterns := []regexp.Regexp{
-		*regexp.MustCompile(`(?i)\b(maybe|perhaps|possibly|might)\b\s+.*\b(add|include|consider|use)\b`),
-		*regexp.MustCompile(`(?i)\b(as needed|if necessary|when appropriate|where applicable|as appropriate)\b`),
-		*regexp.MustCompile(`(?i)\b(etc\.?|and so on|and the like)\b`),
-		*regexp.MustCompile(`(?i)\b(some|various|various different|multiple)\b\s+(things|stuff|items|parts|features)\b`),
-		*regexp.MustCompile(`(?i)\b(in a way that|to be determined|tbd)\b`),
-		*regexp.MustCompile(`(?i)\b(ensure|make sure)\b\s+.*\b(good|better|perfect|nice)\b`),
-	}
-
-	for _, pattern := range vaguePatterns {
-		if pattern.MatchString(prompt) {
-			match := pattern.FindString(prompt)
-			issues = append(issues, "vague language detected: \""+match+"\"")
+	for _, rule := range ambiguityRules {
+		if match := rule.re.FindString(prompt); match != "" {
+			issues = append(issues, rule.Message+": \""+match+"\"")
 		}
 	}
-
 	return issues
 }
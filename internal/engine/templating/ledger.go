@@ -0,0 +1,171 @@
+package templating
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"specfirst/internal/domain"
+)
+
+// knownContextName is the built-in partial that renders the ledger. Templates
+// place it with {{ template "known-context" . }}; prompts whose template does
+// not are given it at the end.
+const knownContextName = "known-context"
+
+// knownContextPartial renders nothing when there are no open assumptions or
+// questions, accepted decisions or open risks.
+const knownContextPartial = `{{ define "known-context" -}}
+{{- $assumptions := openAssumptions -}}
+{{- $questions := openQuestions -}}
+{{- $decisions := acceptedDecisions -}}
+{{- $high := risksBySeverity "high" -}}{{- $medium := risksBySeverity "medium" -}}{{- $low := risksBySeverity "low" -}}
+{{- if or $assumptions $questions $decisions $high $medium $low -}}
+## Known Context
+
+{{ ledgerSummary }}
+{{- with $decisions }}
+
+### Accepted Decisions
+{{- range . }}
+- {{ .Text }}{{ if .Rationale }} (rationale: {{ .Rationale }}){{ end }}
+{{- end }}
+{{- end }}
+{{- with $assumptions }}
+
+### Open Assumptions
+{{- range . }}
+- {{ .Text }}{{ if .Owner }} (owner: {{ .Owner }}){{ end }}
+{{- end }}
+{{- end }}
+{{- with $questions }}
+
+### Open Questions
+{{- range . }}
+- {{ .Text }}{{ if .Tags }} [{{ join .Tags ", " }}]{{ end }}
+{{- end }}
+{{- end }}
+{{- if or $high $medium $low }}
+
+### Open Risks
+{{- range $high }}
+- [high] {{ .Text }}
+{{- end }}
+{{- range $medium }}
+- [medium] {{ .Text }}
+{{- end }}
+{{- range $low }}
+- [low] {{ .Text }}
+{{- end }}
+{{- end }}
+{{ end -}}
+{{- end }}`
+
+// placesKnownContext reports whether a template, or a template it defines,
+// invokes the known-context partial.
+func placesKnownContext(tmpl *template.Template) bool {
+	for _, t := range tmpl.Templates() {
+		if t.Name() != knownContextName && t.Tree != nil && invokesTemplate(t.Tree.Root, knownContextName) {
+			return true
+		}
+	}
+	return false
+}
+
+func invokesTemplate(node parse.Node, name string) bool {
+	switch n := node.(type) {
+	case *parse.TemplateNode:
+		return n.Name == name
+	case *parse.ListNode:
+		if n == nil {
+			return false
+		}
+		for _, child := range n.Nodes {
+			if invokesTemplate(child, name) {
+				return true
+			}
+		}
+	case *parse.IfNode:
+		return invokesTemplate(n.List, name) || invokesTemplate(n.ElseList, name)
+	case *parse.RangeNode:
+		return invokesTemplate(n.List, name) || invokesTemplate(n.ElseList, name)
+	case *parse.WithNode:
+		return invokesTemplate(n.List, name) || invokesTemplate(n.ElseList, name)
+	}
+	return false
+}
+
+// openAssumptions returns the assumptions that are neither validated nor
+// invalidated.
+func openAssumptions(e domain.Epistemics) []domain.Assumption {
+	var open []domain.Assumption
+	for _, a := range e.Assumptions {
+		if a.Status == "open" {
+			open = append(open, a)
+		}
+	}
+	return open
+}
+
+// openQuestions returns the open questions, limited to those carrying at
+// least one of tags when any are given.
+func openQuestions(e domain.Epistemics, tags []string) []domain.OpenQuestion {
+	var open []domain.OpenQuestion
+	for _, q := range e.OpenQuestions {
+		if q.Status != "open" {
+			continue
+		}
+		if len(tags) == 0 || hasAnyTag(q.Tags, tags) {
+			open = append(open, q)
+		}
+	}
+	return open
+}
+
+func hasAnyTag(tags, wanted []string) bool {
+	for _, tag := range tags {
+		for _, w := range wanted {
+			if strings.EqualFold(tag, w) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// risksBySeverity returns the risks of a severity that are neither mitigated
+// nor accepted.
+func risksBySeverity(e domain.Epistemics, severity string) []domain.Risk {
+	var risks []domain.Risk
+	for _, r := range e.Risks {
+		if strings.EqualFold(r.Severity, severity) && r.Status != "mitigated" && r.Status != "accepted" {
+			risks = append(risks, r)
+		}
+	}
+	return risks
+}
+
+func acceptedDecisions(e domain.Epistemics) []domain.Decision {
+	var accepted []domain.Decision
+	for _, d := range e.Decisions {
+		if d.Status == "accepted" {
+			accepted = append(accepted, d)
+		}
+	}
+	return accepted
+}
+
+// ledgerSummary describes the ledger in one line, e.g. "Ledger: 2 accepted
+// decision(s), 1 open assumption(s), 3 open question(s), 1 open risk(s) (1
+// high)."
+func ledgerSummary(e domain.Epistemics) string {
+	open := 0
+	for _, r := range e.Risks {
+		if r.Status != "mitigated" && r.Status != "accepted" {
+			open++
+		}
+	}
+	return fmt.Sprintf("Ledger: %d accepted decision(s), %d open assumption(s), %d open question(s), %d open risk(s) (%d high).",
+		len(acceptedDecisions(e)), len(openAssumptions(e)), len(openQuestions(e, nil)), open, len(risksBySeverity(e, "high")))
+}
@@ -7,7 +7,9 @@ import (
 	"path/filepath"
 	"strings"
 	"text/template"
+	"time"
 
+	"specfirst/internal/domain"
 	"specfirst/internal/repository"
 )
 
@@ -16,6 +18,13 @@ type Input struct {
 	Content string
 }
 
+// Iteration is one recorded completion of a repeatable stage.
+type Iteration struct {
+	Number      int
+	CompletedAt time.Time
+	Artifacts   []Input
+}
+
 type Data struct {
 	StageName   string
 	ProjectName string
@@ -31,10 +40,17 @@ type Data struct {
 	StageType      string
 	Prompt         any
 	OutputContract any
-	Epistemics     any
+	Epistemics     domain.Epistemics
+
+	// KnownContext appends the "known-context" block to the rendered
+	// prompt unless the template already includes it.
+	KnownContext bool
+
+	// Previous iterations of a repeatable stage, oldest first
+	Iterations []Iteration
 }
 
-func templateFuncMap() template.FuncMap {
+func templateFuncMap(e domain.Epistemics) template.FuncMap {
 	return template.FuncMap{
 		"join": func(items []string, sep string) string {
 			return strings.Join(items, sep)
@@ -42,6 +58,12 @@ func templateFuncMap() template.FuncMap {
 		"upper":    strings.ToUpper,
 		"lower":    strings.ToLower,
 		"readFile": readFile,
+
+		"openAssumptions":   func() []domain.Assumption { return openAssumptions(e) },
+		"openQuestions":     func(tags ...string) []domain.OpenQuestion { return openQuestions(e, tags) },
+		"risksBySeverity":   func(severity string) []domain.Risk { return risksBySeverity(e, severity) },
+		"acceptedDecisions": func() []domain.Decision { return acceptedDecisions(e) },
+		"ledgerSummary":     func() string { return ledgerSummary(e) },
 	}
 }
 
@@ -116,7 +138,10 @@ func Render(templatePath string, data Data) (string, error) {
 		return "", fmt.Errorf("reading template %s: %w", templatePath, err)
 	}
 
-	tmpl, err := template.New(templatePath).Funcs(templateFuncMap()).Parse(string(content))
+	tmpl, err := template.New(templatePath).Funcs(templateFuncMap(data.Epistemics)).Parse(knownContextPartial)
+	if err == nil {
+		_, err = tmpl.Parse(string(content))
+	}
 	if err != nil {
 		return "", fmt.Errorf("parsing template %s: %w", templatePath, err)
 	}
@@ -126,12 +151,31 @@ func Render(templatePath string, data Data) (string, error) {
 		return "", fmt.Errorf("executing template %s: %w", templatePath, err)
 	}
 
+	if data.KnownContext && !placesKnownContext(tmpl) {
+		var block bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&block, knownContextName, data); err != nil {
+			return "", fmt.Errorf("executing template %s: %w", templatePath, err)
+		}
+		if block.Len() > 0 {
+			out := strings.TrimRight(buf.String(), "\n")
+			if out != "" {
+				out += "\n\n"
+			}
+			return out + block.String(), nil
+		}
+	}
+
 	return buf.String(), nil
 }
 
-// RenderInline renders an inline template string.
+// RenderInline renders an inline template string. Ledger helpers read the
+// epistemics of data when it is a Data.
 func RenderInline(tmpl string, data any) (string, error) {
-	parsed, err := template.New("inline").Funcs(templateFuncMap()).Parse(tmpl)
+	var e domain.Epistemics
+	if d, ok := data.(Data); ok {
+		e = d.Epistemics
+	}
+	parsed, err := template.New("inline").Funcs(templateFuncMap(e)).Parse(tmpl)
 	if err != nil {
 		return "", err
 	}
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"testing"
 
+	"specfirst/internal/domain"
 	"specfirst/internal/repository"
 )
 
@@ -79,3 +80,104 @@ func TestRender_ReadFileRejectsTraversal(t *testing.T) {
 		t.Fatalf("expected error")
 	}
 }
+
+func TestRender_LedgerHelpersAndKnownContext(t *testing.T) {
+	root := t.TempDir()
+	ledger := domain.Epistemics{
+		Assumptions:   []domain.Assumption{{Text: "SSO only", Status: "open", Owner: "ana"}, {Text: "Old", Status: "validated"}},
+		OpenQuestions: []domain.OpenQuestion{{Text: "Which region?", Tags: []string{"infra"}, Status: "open"}, {Text: "Key rotation?", Tags: []string{"security"}, Status: "open"}, {Text: "Done?", Status: "resolved"}},
+		Decisions:     []domain.Decision{{Text: "Use Postgres", Rationale: "JSON columns", Status: "accepted"}, {Text: "Shard", Status: "proposed"}},
+		Risks:         []domain.Risk{{Text: "Rate limits", Severity: "high", Status: "open"}, {Text: "Drift", Severity: "low", Status: "open"}, {Text: "Cost", Severity: "high", Status: "mitigated"}},
+	}
+
+	tplPath := filepath.Join(root, "helpers.md")
+	tpl := `{{ range openQuestions "security" }}q={{ .Text }}{{ end }}
+{{ range risksBySeverity "high" }}r={{ .Text }}{{ end }}
+{{ range acceptedDecisions }}d={{ .Text }}{{ end }}
+{{ ledgerSummary }}
+`
+	if err := os.WriteFile(tplPath, []byte(tpl), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out, err := Render(tplPath, Data{Epistemics: ledger})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "q=Key rotation?\nr=Rate limits\nd=Use Postgres\nLedger: 1 accepted decision(s), 1 open assumption(s), 2 open question(s), 2 open risk(s) (1 high).\n"
+	if out != want {
+		t.Fatalf("unexpected helper output:\n%s", out)
+	}
+
+	// The Known Context block is appended when the template does not place it.
+	plainPath := filepath.Join(root, "plain.md")
+	if err := os.WriteFile(plainPath, []byte("# Design\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out, err = Render(plainPath, Data{Epistemics: ledger, KnownContext: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = `# Design
+
+## Known Context
+
+Ledger: 1 accepted decision(s), 1 open assumption(s), 2 open question(s), 2 open risk(s) (1 high).
+
+### Accepted Decisions
+- Use Postgres (rationale: JSON columns)
+
+### Open Assumptions
+- SSO only (owner: ana)
+
+### Open Questions
+- Which region? [infra]
+- Key rotation? [security]
+
+### Open Risks
+- [high] Rate limits
+- [low] Drift
+`
+	if out != want {
+		t.Fatalf("unexpected known context:\n%s", out)
+	}
+
+	// Templates that place the partial themselves get it once; an empty ledger renders nothing.
+	placedPath := filepath.Join(root, "placed.md")
+	if err := os.WriteFile(placedPath, []byte("{{ template \"known-context\" . }}# Design\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out, err = Render(placedPath, Data{Epistemics: ledger, KnownContext: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(out, "## Known Context") != 1 || !strings.HasSuffix(out, "# Design\n") {
+		t.Fatalf("expected the placed partial only:\n%s", out)
+	}
+
+	// Placing the partial inside a conditional counts; merely mentioning it does not.
+	nestedPath := filepath.Join(root, "nested.md")
+	if err := os.WriteFile(nestedPath, []byte("{{ if .Epistemics.Risks }}{{ template \"known-context\" . }}{{ end }}# Design\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out, err = Render(nestedPath, Data{Epistemics: ledger, KnownContext: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(out, "## Known Context") != 1 || !strings.HasSuffix(out, "# Design\n") {
+		t.Fatalf("expected the nested partial only:\n%s", out)
+	}
+	mentionPath := filepath.Join(root, "mention.md")
+	if err := os.WriteFile(mentionPath, []byte("# Design\n\nSee the known-context section below.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out, err = Render(mentionPath, Data{Epistemics: ledger, KnownContext: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "section below.\n\n## Known Context") {
+		t.Fatalf("expected the block after a template that only mentions it:\n%s", out)
+	}
+	if out, err := Render(plainPath, Data{KnownContext: true}); err != nil || out != "# Design\n" {
+		t.Fatalf("expected no block for an empty ledger, got %q (%v)", out, err)
+	}
+}
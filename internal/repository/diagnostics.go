@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Diagnostic is a protocol problem located in its source file.
+type Diagnostic struct {
+	Severity string `json:"severity"`
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Stage    string `json:"stage,omitempty"`
+	Message  string `json:"message"`
+}
+
+// String formats the diagnostic as file:line:column: severity: message.
+func (d Diagnostic) String() string {
+	location := d.File
+	if d.Line > 0 {
+		location = fmt.Sprintf("%s:%d:%d", d.File, d.Line, d.Column)
+	}
+	return fmt.Sprintf("%s: %s: %s", location, d.Severity, d.Message)
+}
+
+// DiagnosticsError reports every error-level diagnostic found while loading a protocol.
+type DiagnosticsError struct {
+	Diagnostics []Diagnostic
+}
+
+func (e *DiagnosticsError) Error() string {
+	if len(e.Diagnostics) == 1 {
+		return e.Diagnostics[0].String()
+	}
+	lines := make([]string, 0, len(e.Diagnostics)+1)
+	lines = append(lines, fmt.Sprintf("%d protocol errors:", len(e.Diagnostics)))
+	for _, d := range e.Diagnostics {
+		lines = append(lines, "  "+d.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// diagnosticsError returns a DiagnosticsError for the error-level diagnostics, or nil.
+func diagnosticsError(diags []Diagnostic) error {
+	var errs []Diagnostic
+	for _, d := range diags {
+		if d.Severity == SeverityError {
+			errs = append(errs, d)
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return &DiagnosticsError{Diagnostics: errs}
+}
+
+// nodeRef points at a YAML node in a protocol file. A nil node (e.g. for
+// protocols supplied by a resolver) yields diagnostics without a position.
+// Overridden stages keep the inherited definition as a fallback, so fields
+// that were not overridden still point into the file that declared them.
+type nodeRef struct {
+	file     string
+	node     *yaml.Node
+	fallback *nodeRef
+}
+
+// field returns the value of key in a mapping node, or the node itself when
+// the key is absent so diagnostics still point at the enclosing element.
+func (r nodeRef) field(key string) nodeRef {
+	if r.node != nil && r.node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(r.node.Content); i += 2 {
+			if r.node.Content[i].Value == key {
+				return nodeRef{file: r.file, node: r.node.Content[i+1]}
+			}
+		}
+	}
+	if r.fallback != nil {
+		if found := r.fallback.field(key); found.node != r.fallback.node {
+			return found
+		}
+	}
+	return r
+}
+
+// item returns the i-th element of a sequence node, or the node itself.
+func (r nodeRef) item(i int) nodeRef {
+	if r.node == nil || r.node.Kind != yaml.SequenceNode || i < 0 || i >= len(r.node.Content) {
+		return r
+	}
+	return nodeRef{file: r.file, node: r.node.Content[i]}
+}
+
+// items returns a ref for every element of a sequence node.
+func (r nodeRef) items(n int) []nodeRef {
+	refs := make([]nodeRef, n)
+	for i := range refs {
+		refs[i] = r.item(i)
+	}
+	return refs
+}
+
+func (r nodeRef) diagnostic(severity, stage, format string, args ...any) Diagnostic {
+	d := Diagnostic{
+		Severity: severity,
+		File:     r.file,
+		Stage:    stage,
+		Message:  fmt.Sprintf(format, args...),
+	}
+	if r.node != nil {
+		d.Line = r.node.Line
+		d.Column = r.node.Column
+	}
+	return d
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"specfirst/internal/domain"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadLintRules reads the project's lint rules file, which has the shape of a
+// protocol lint block. A missing file yields nil.
+func LoadLintRules(path string) (*domain.LintConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var cfg domain.LintConfig
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for rule, level := range cfg.Severity {
+		if !domain.IsLintSeverity(level) {
+			return nil, fmt.Errorf("%s: lint severity for %q must be error, warning, note or off (got %q)", path, rule, level)
+		}
+	}
+	seen := make(map[string]bool)
+	for _, rule := range cfg.Rules {
+		if err := rule.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if seen[rule.ID] {
+			return nil, fmt.Errorf("%s: duplicate rule id %q", path, rule.ID)
+		}
+		seen[rule.ID] = true
+	}
+	return &cfg, nil
+}
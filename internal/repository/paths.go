@@ -24,16 +24,19 @@ func ResetRootDir() {
 }
 
 const (
-	SpecDir      = ".specfirst"
-	ArtifactsDir = "artifacts"
-	GeneratedDir = "generated"
-	ProtocolsDir = "protocols"
-	TemplatesDir = "templates"
-	ArchivesDir  = "archives"
-	TracksDir    = "tracks"
-	SkillsDir    = "skills"
-	StateFile    = "state.json"
-	ConfigFile   = "config.yaml"
+	SpecDir       = ".specfirst"
+	ArtifactsDir  = "artifacts"
+	GeneratedDir  = "generated"
+	ProtocolsDir  = "protocols"
+	TemplatesDir  = "templates"
+	ArchivesDir   = "archives"
+	HistoryDir    = "history"
+	SchemasDir    = "schemas"
+	TracksDir     = "tracks"
+	SkillsDir     = "skills"
+	StateFile     = "state.json"
+	ConfigFile    = "config.yaml"
+	LintRulesFile = "lint-rules.yaml"
 )
 
 func SpecPath(elem ...string) string {
@@ -51,6 +54,19 @@ func GeneratedPath(elem ...string) string {
 	return SpecPath(parts...)
 }
 
+// HistoryPath returns a path inside the iteration history of repeatable stages.
+func HistoryPath(elem ...string) string {
+	parts := append([]string{HistoryDir}, elem...)
+	return SpecPath(parts...)
+}
+
+// SchemasPath returns a path inside the directory of JSON Schemas referenced
+// by output contracts.
+func SchemasPath(elem ...string) string {
+	parts := append([]string{SchemasDir}, elem...)
+	return SpecPath(parts...)
+}
+
 func ProtocolsPath(elem ...string) string {
 	parts := append([]string{ProtocolsDir}, elem...)
 	return SpecPath(parts...)
@@ -84,6 +100,11 @@ func ConfigPath() string {
 	return SpecPath(ConfigFile)
 }
 
+// LintRulesPath returns the path of the project's custom lint rules file.
+func LintRulesPath() string {
+	return SpecPath(LintRulesFile)
+}
+
 func BaseDir() string {
 	// If a root directory has been injected (for testing), use it.
 	if rootDir != "" {
@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"specfirst/internal/domain"
+
+	"gopkg.in/yaml.v3"
+)
+
+// resolveInheritance flattens a protocol that extends another: inherited stages
+// are dropped by remove, patched by overrides, and the protocol's own stages are
+// placed by before/after (or appended). Protocols without extends still have
+// their before/after placement applied.
+func resolveInheritance(raw rawProtocol, path string, resolver func(name string) (domain.Protocol, error), stack []string, cache map[string]rawProtocol) (rawProtocol, []Diagnostic) {
+	var diags []Diagnostic
+	p := raw.proto
+
+	var base rawProtocol
+	if p.Extends != "" {
+		var err error
+		base, diags, err = loadParentProtocol(p.Extends, path, resolver, stack, cache)
+		if err != nil {
+			diags = append(diags, raw.root.field("extends").diagnostic(SeverityError, "", "extending %q: %v", p.Extends, err))
+		}
+	}
+
+	stages := append([]domain.Stage{}, base.proto.Stages...)
+	refs := append([]nodeRef{}, base.stages...)
+
+	// Remove inherited stages
+	removed := make(map[string]bool)
+	removeRef := raw.root.field("remove")
+	for i, id := range p.Remove {
+		idx := stageIndex(stages, id)
+		if idx < 0 {
+			diags = append(diags, removeRef.item(i).diagnostic(SeverityError, id, "cannot remove %q: no inherited stage with that id", id))
+			continue
+		}
+		stages = append(stages[:idx], stages[idx+1:]...)
+		refs = append(refs[:idx], refs[idx+1:]...)
+		removed[id] = true
+	}
+
+	// Apply field-level overrides to inherited stages
+	overridesRef := raw.root.field("overrides")
+	for i, override := range p.Overrides {
+		ref := overridesRef.item(i)
+		id, _ := override["id"].(string)
+		if id == "" {
+			diags = append(diags, ref.diagnostic(SeverityError, "", "override is missing a stage id"))
+			continue
+		}
+		idx := stageIndex(stages, id)
+		if idx < 0 {
+			diags = append(diags, ref.field("id").diagnostic(SeverityError, id, "override for unknown stage %q", id))
+			continue
+		}
+		stage, err := overrideStage(stages[idx], ref.node)
+		if err != nil {
+			diags = append(diags, ref.diagnostic(SeverityError, id, "override for stage %q: %v", id, err))
+			continue
+		}
+		inherited := refs[idx]
+		stages[idx] = stage
+		refs[idx] = nodeRef{file: ref.file, node: ref.node, fallback: &inherited}
+	}
+
+	// Place the protocol's own stages
+	for i, stage := range p.Stages {
+		ref := raw.stages[i]
+		anchor, offset, key := stage.After, 1, "after"
+		if stage.Before != "" {
+			anchor, offset, key = stage.Before, 0, "before"
+			if stage.After != "" {
+				diags = append(diags, ref.field("before").diagnostic(SeverityError, stage.ID, "stage %q cannot set both before and after", stage.ID))
+			}
+		}
+		stage.Before, stage.After = "", ""
+
+		existing := stageIndex(stages, stage.ID)
+		if anchor == "" && existing >= 0 {
+			// Redefining an inherited stage replaces it in place.
+			stages[existing], refs[existing] = stage, ref
+			continue
+		}
+		if existing >= 0 {
+			stages = append(stages[:existing], stages[existing+1:]...)
+			refs = append(refs[:existing], refs[existing+1:]...)
+		}
+
+		pos := len(stages)
+		if anchor != "" {
+			if idx := stageIndex(stages, anchor); idx >= 0 {
+				pos = idx + offset
+			} else {
+				diags = append(diags, ref.field(key).diagnostic(SeverityError, stage.ID, "stage %q is placed %s unknown stage %q", stage.ID, key, anchor))
+			}
+		}
+		stages = append(stages[:pos], append([]domain.Stage{stage}, stages[pos:]...)...)
+		refs = append(refs[:pos], append([]nodeRef{ref}, refs[pos:]...)...)
+	}
+
+	// Inherit approvals for stages that were not removed
+	var approvals []domain.Approval
+	var approvalRefs []nodeRef
+	for i, approval := range base.proto.Approvals {
+		if removed[approval.Stage] {
+			continue
+		}
+		approvals = append(approvals, approval)
+		approvalRefs = append(approvalRefs, base.approvals[i])
+	}
+	raw.proto.Approvals = append(approvals, raw.proto.Approvals...)
+	raw.approvals = append(approvalRefs, raw.approvals...)
+
+	if !raw.proto.StrictContract {
+		raw.proto.StrictContract = base.proto.StrictContract
+	}
+	if raw.proto.Lint == nil {
+		raw.proto.Lint = base.proto.Lint
+	}
+	raw.proto.Stages = stages
+	raw.stages = refs
+	raw.proto.Extends = ""
+	raw.proto.Overrides = nil
+	raw.proto.Remove = nil
+	return raw, diags
+}
+
+// loadParentProtocol loads the protocol named by extends, resolved like uses.
+func loadParentProtocol(name, path string, resolver func(name string) (domain.Protocol, error), stack []string, cache map[string]rawProtocol) (rawProtocol, []Diagnostic, error) {
+	if resolver != nil {
+		proto, err := resolver(name)
+		unknown := nodeRef{file: name}
+		return rawProtocol{
+			proto:     proto,
+			stages:    unknown.items(len(proto.Stages)),
+			approvals: unknown.items(len(proto.Approvals)),
+		}, nil, err
+	}
+	return loadRawProtocol(filepath.Join(filepath.Dir(path), name+".yaml"), nil, stack, cache)
+}
+
+// overrideStage merges an override mapping into an inherited stage. Mappings
+// are merged key by key (so prompt or output settings can be patched
+// individually); scalars and lists replace the inherited value. The override
+// is merged onto the resolved stage, not its source node, so patches from
+// every level of an extends chain are kept.
+func overrideStage(stage domain.Stage, override *yaml.Node) (domain.Stage, error) {
+	if override == nil || override.Kind != yaml.MappingNode {
+		return domain.Stage{}, fmt.Errorf("override must be a mapping")
+	}
+	inherited := &yaml.Node{}
+	if err := inherited.Encode(stage); err != nil {
+		return domain.Stage{}, err
+	}
+	var merged domain.Stage
+	if err := mergeNodes(inherited, override).Decode(&merged); err != nil {
+		return domain.Stage{}, err
+	}
+	return merged, nil
+}
+
+// mergeNodes returns override merged onto base without modifying either.
+func mergeNodes(base, override *yaml.Node) *yaml.Node {
+	if base.Kind != yaml.MappingNode || override.Kind != yaml.MappingNode {
+		return override
+	}
+	merged := &yaml.Node{Kind: yaml.MappingNode, Tag: base.Tag, Line: override.Line, Column: override.Column}
+	merged.Content = append(merged.Content, base.Content...)
+	for i := 0; i+1 < len(override.Content); i += 2 {
+		key, value := override.Content[i], override.Content[i+1]
+		replaced := false
+		for j := 0; j+1 < len(merged.Content); j += 2 {
+			if merged.Content[j].Value == key.Value {
+				merged.Content[j+1] = mergeNodes(merged.Content[j+1], value)
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged.Content = append(merged.Content, key, value)
+		}
+	}
+	return merged
+}
+
+func stageIndex(stages []domain.Stage, id string) int {
+	for i, stage := range stages {
+		if stage.ID == id {
+			return i
+		}
+	}
+	return -1
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 
 	"specfirst/internal/domain"
@@ -28,220 +29,513 @@ func LoadProtocol(path string) (domain.Protocol, error) {
 }
 
 // LoadProtocolWithResolver loads a protocol with a custom resolver for imports.
+// All validation errors are reported together as a *DiagnosticsError.
 func LoadProtocolWithResolver(path string, resolver func(name string) (domain.Protocol, error)) (domain.Protocol, error) {
-	return loadProtocolWithDepth(path, resolver, make([]string, 0), make(map[string]domain.Protocol))
+	p, diags, err := checkProtocol(path, resolver)
+	if err != nil {
+		return domain.Protocol{}, err
+	}
+	if err := diagnosticsError(diags); err != nil {
+		return domain.Protocol{}, err
+	}
+	return p, nil
+}
+
+// CheckProtocol loads a protocol and returns every error and warning found in
+// it and its imports. The error is only set when the file itself cannot be read
+// or parsed.
+func CheckProtocol(path string) (domain.Protocol, []Diagnostic, error) {
+	return checkProtocol(path, nil)
 }
 
-func loadProtocolWithDepth(path string, resolver func(name string) (domain.Protocol, error), visitedStack []string, processedCache map[string]domain.Protocol) (domain.Protocol, error) {
+func checkProtocol(path string, resolver func(name string) (domain.Protocol, error)) (domain.Protocol, []Diagnostic, error) {
+	raw, diags, err := loadRawProtocol(path, resolver, make([]string, 0), make(map[string]rawProtocol))
+	if err != nil {
+		return domain.Protocol{}, nil, err
+	}
+	diags = append(diags, validateProtocol(raw)...)
+	diags = append(diags, protocolWarnings(raw)...)
+	return raw.proto, diags, nil
+}
+
+// rawProtocol is a parsed protocol with imports merged, before validation,
+// together with the source location of each stage and approval.
+type rawProtocol struct {
+	proto     domain.Protocol
+	root      nodeRef
+	stages    []nodeRef // parallel to proto.Stages
+	approvals []nodeRef // parallel to proto.Approvals
+}
+
+func loadRawProtocol(path string, resolver func(name string) (domain.Protocol, error), visitedStack []string, processedCache map[string]rawProtocol) (rawProtocol, []Diagnostic, error) {
 	abs, err := filepath.Abs(path)
 	if err != nil {
-		return domain.Protocol{}, err
+		return rawProtocol{}, nil, err
 	}
 
 	// 1. Cycle Detection
 	for _, visited := range visitedStack {
 		if visited == abs {
-			return domain.Protocol{}, fmt.Errorf("circular protocol import detected: %s", abs)
+			return rawProtocol{}, nil, fmt.Errorf("circular protocol import detected: %s", abs)
 		}
 	}
 
 	// 2. Memoization
 	if cached, ok := processedCache[abs]; ok {
-		return cached, nil
+		return cached, nil, nil
 	}
 
-	// 3. Load and Parse
+	// 3. Load and Parse (keeping the node tree for source positions)
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return domain.Protocol{}, err
+		return rawProtocol{}, nil, err
 	}
 
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return rawProtocol{}, nil, fmt.Errorf("%s: %w", path, err)
+	}
 	var p domain.Protocol
-	if err := yaml.Unmarshal(data, &p); err != nil {
-		return domain.Protocol{}, err
+	root := nodeRef{file: path}
+	if len(doc.Content) > 0 {
+		root.node = doc.Content[0]
+		if err := root.node.Decode(&p); err != nil {
+			return rawProtocol{}, nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	raw := rawProtocol{
+		proto:     p,
+		root:      root,
+		stages:    root.field("stages").items(len(p.Stages)),
+		approvals: root.field("approvals").items(len(p.Approvals)),
 	}
+	var diags []Diagnostic
 
 	// 4. Resolve Imports
 	if len(p.Uses) > 0 {
 		baseDir := filepath.Dir(path)
 		newStack := append(visitedStack, abs)
+		usesRef := root.field("uses")
 
 		for i := len(p.Uses) - 1; i >= 0; i-- {
 			importPath := p.Uses[i]
-			var imported domain.Protocol
+			var imported rawProtocol
 			var importErr error
 
 			if resolver != nil {
-				imported, importErr = resolver(importPath)
+				var proto domain.Protocol
+				proto, importErr = resolver(importPath)
+				unknown := nodeRef{file: importPath}
+				imported = rawProtocol{
+					proto:     proto,
+					stages:    unknown.items(len(proto.Stages)),
+					approvals: unknown.items(len(proto.Approvals)),
+				}
 			} else {
 				// Default: resolve relative to protocol directory
 				importFile := filepath.Join(baseDir, importPath+".yaml")
-				imported, importErr = loadProtocolWithDepth(importFile, nil, newStack, processedCache)
+				var importDiags []Diagnostic
+				imported, importDiags, importErr = loadRawProtocol(importFile, nil, newStack, processedCache)
+				diags = append(diags, importDiags...)
 			}
 
 			if importErr != nil {
-				return domain.Protocol{}, fmt.Errorf("importing %q: %w", importPath, importErr)
+				diags = append(diags, usesRef.item(i).diagnostic(SeverityError, "", "importing %q: %v", importPath, importErr))
+				continue
 			}
 
 			// Prepend imported stages
-			p.Stages = append(imported.Stages, p.Stages...)
+			raw.proto.Stages = append(append([]domain.Stage{}, imported.proto.Stages...), raw.proto.Stages...)
+			raw.stages = append(append([]nodeRef{}, imported.stages...), raw.stages...)
 			// Merge approvals
-			p.Approvals = append(imported.Approvals, p.Approvals...)
+			raw.proto.Approvals = append(append([]domain.Approval{}, imported.proto.Approvals...), raw.proto.Approvals...)
+			raw.approvals = append(append([]nodeRef{}, imported.approvals...), raw.approvals...)
 		}
 	}
 
-	// Deduplicate Stages
+	// Deduplicate Stages (last occurrence wins)
 	uniqueStages := make(map[string]bool)
 	var dedupedStages []domain.Stage
-	for i := len(p.Stages) - 1; i >= 0; i-- {
-		s := p.Stages[i]
+	var dedupedStageRefs []nodeRef
+	for i := len(raw.proto.Stages) - 1; i >= 0; i-- {
+		s := raw.proto.Stages[i]
 		if !uniqueStages[s.ID] {
 			uniqueStages[s.ID] = true
 			dedupedStages = append([]domain.Stage{s}, dedupedStages...)
+			dedupedStageRefs = append([]nodeRef{raw.stages[i]}, dedupedStageRefs...)
 		}
 	}
-	p.Stages = dedupedStages
+	raw.proto.Stages = dedupedStages
+	raw.stages = dedupedStageRefs
+
+	// 5. Resolve Inheritance (extends/overrides/remove, before/after placement)
+	var inheritDiags []Diagnostic
+	raw, inheritDiags = resolveInheritance(raw, path, resolver, append(visitedStack, abs), processedCache)
+	diags = append(diags, inheritDiags...)
 
 	// Deduplicate Approvals
 	uniqueApprovals := make(map[string]bool)
 	var dedupedApprovals []domain.Approval
-	for i := len(p.Approvals) - 1; i >= 0; i-- {
-		a := p.Approvals[i]
+	var dedupedApprovalRefs []nodeRef
+	for i := len(raw.proto.Approvals) - 1; i >= 0; i-- {
+		a := raw.proto.Approvals[i]
 		key := a.Stage + "::" + a.Role
 		if !uniqueApprovals[key] {
 			uniqueApprovals[key] = true
 			dedupedApprovals = append([]domain.Approval{a}, dedupedApprovals...)
+			dedupedApprovalRefs = append([]nodeRef{raw.approvals[i]}, dedupedApprovalRefs...)
 		}
 	}
-	p.Approvals = dedupedApprovals
+	raw.proto.Approvals = dedupedApprovals
+	raw.approvals = dedupedApprovalRefs
+
+	processedCache[abs] = raw
+	return raw, diags, nil
+}
 
-	// Validation
+// validateProtocol returns an error diagnostic for every structural problem.
+func validateProtocol(raw rawProtocol) []Diagnostic {
+	p := raw.proto
+	var diags []Diagnostic
 	seen := make(map[string]bool)
 	stageMap := make(map[string]domain.Stage)
 
-	for _, stage := range p.Stages {
+	for i, stage := range p.Stages {
+		ref := raw.stages[i]
 		if err := validateStageID(stage.ID); err != nil {
-			return domain.Protocol{}, err
+			diags = append(diags, ref.field("id").diagnostic(SeverityError, stage.ID, "%v", err))
 		}
 		if err := validateTemplatePath(stage.Template); err != nil {
-			return domain.Protocol{}, err
+			diags = append(diags, ref.field("template").diagnostic(SeverityError, stage.ID, "stage %q: %v", stage.ID, err))
 		}
 		if err := validateStageType(stage.Type); err != nil {
-			return domain.Protocol{}, fmt.Errorf("stage %q: %w", stage.ID, err)
+			diags = append(diags, ref.field("type").diagnostic(SeverityError, stage.ID, "stage %q: %v", stage.ID, err))
 		}
 		if seen[stage.ID] {
-			return domain.Protocol{}, fmt.Errorf("duplicate stage ID: %s", stage.ID)
+			diags = append(diags, ref.field("id").diagnostic(SeverityError, stage.ID, "duplicate stage ID: %s", stage.ID))
 		}
 		seen[stage.ID] = true
 		stageMap[stage.ID] = stage
 	}
 
 	// Dependency Validation
-	for _, stage := range p.Stages {
-		for _, dep := range stage.DependsOn {
+	for i, stage := range p.Stages {
+		deps := raw.stages[i].field("depends_on")
+		for j, dep := range stage.DependsOn {
 			if dep == stage.ID {
-				return domain.Protocol{}, fmt.Errorf("stage %q cannot depend on itself", stage.ID)
-			}
-			if !seen[dep] {
-				return domain.Protocol{}, fmt.Errorf("stage %q depends on unknown stage %q", stage.ID, dep)
+				diags = append(diags, deps.item(j).diagnostic(SeverityError, stage.ID, "stage %q cannot depend on itself", stage.ID))
+			} else if !seen[dep] {
+				diags = append(diags, deps.item(j).diagnostic(SeverityError, stage.ID, "stage %q depends on unknown stage %q", stage.ID, dep))
 			}
 		}
 	}
 
 	// Input Validation
-	for _, stage := range p.Stages {
-		for _, input := range stage.Inputs {
+	for i, stage := range p.Stages {
+		inputs := raw.stages[i].field("inputs")
+		for j, input := range stage.Inputs {
 			if input == "" {
 				continue
 			}
-			found := false
+			if len(inputProducers(stageMap, stage, input)) == 0 {
+				diags = append(diags, inputs.item(j).diagnostic(SeverityError, stage.ID, "stage %q: input %q not found in outputs of any dependency", stage.ID, input))
+			}
+		}
+	}
+
+	// Task Prompt Validation
+	for i, stage := range p.Stages {
+		if stage.Type == "task_prompt" && stage.Source != "" {
+			source := raw.stages[i].field("source")
+			if !seen[stage.Source] {
+				diags = append(diags, source.diagnostic(SeverityError, stage.ID, "stage %q references unknown source stage %q", stage.ID, stage.Source))
+				continue
+			}
+			if stageMap[stage.Source].Type != "decompose" {
+				diags = append(diags, source.diagnostic(SeverityError, stage.ID, "stage %q source must be a decompose stage", stage.ID))
+			}
+		}
+	}
 
-			for _, depID := range stage.DependsOn {
-				depStage := stageMap[depID]
-				for _, out := range depStage.Outputs {
-					if matchPattern(out, input) {
-						found = true
-						break
-					}
-				}
-				if found {
+	// Condition Validation
+	for i, stage := range p.Stages {
+		if stage.When == "" {
+			continue
+		}
+		when := raw.stages[i].field("when")
+		if _, err := domain.ParseCondition(stage.When); err != nil {
+			diags = append(diags, when.diagnostic(SeverityError, stage.ID, "stage %q: %v", stage.ID, err))
+		}
+		if stage.Terminal {
+			diags = append(diags, when.diagnostic(SeverityError, stage.ID, "terminal stage %q cannot have a when condition", stage.ID))
+		}
+	}
+
+	// Output Contract Validation
+	for i, stage := range p.Stages {
+		if stage.Output == nil || stage.Output.Schema == "" {
+			continue
+		}
+		schema := raw.stages[i].field("output").field("schema")
+		if stage.Output.Format != "json" && stage.Output.Format != "yaml" {
+			diags = append(diags, schema.diagnostic(SeverityError, stage.ID, "stage %q: output schema requires format json or yaml", stage.ID))
+		}
+		clean := filepath.Clean(filepath.FromSlash(stage.Output.Schema))
+		if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(os.PathSeparator)) {
+			diags = append(diags, schema.diagnostic(SeverityError, stage.ID, "stage %q: schema path %q must be relative to %s", stage.ID, stage.Output.Schema, SchemasDir))
+		}
+	}
+
+	// Lint Validation
+	if p.Lint != nil {
+		diags = append(diags, lintDiagnostics(p.Lint, raw.root.field("lint"), "", seen)...)
+	}
+	for i, stage := range p.Stages {
+		if stage.Prompt != nil && stage.Prompt.Lint != nil {
+			diags = append(diags, lintDiagnostics(stage.Prompt.Lint, raw.stages[i].field("prompt").field("lint"), stage.ID, seen)...)
+		}
+	}
+
+	// Cycle Detection
+	diags = append(diags, dependencyCycles(raw, seen)...)
+
+	// Approval Validation
+	for i, approval := range p.Approvals {
+		ref := raw.approvals[i]
+		if strings.TrimSpace(approval.Stage) == "" {
+			diags = append(diags, ref.diagnostic(SeverityError, "", "approval references empty stage"))
+			continue
+		}
+		if strings.TrimSpace(approval.Role) == "" {
+			diags = append(diags, ref.diagnostic(SeverityError, approval.Stage, "approval role is required for stage %q", approval.Stage))
+		}
+		if !seen[approval.Stage] {
+			diags = append(diags, ref.field("stage").diagnostic(SeverityError, approval.Stage, "approval references unknown stage %q", approval.Stage))
+		}
+	}
+
+	return diags
+}
+
+// inputProducers returns the IDs of the stages whose outputs match input: the
+// stage's dependencies, or the stage named by a stage-qualified input
+// ("stage-id/file").
+func inputProducers(stageMap map[string]domain.Stage, stage domain.Stage, input string) []string {
+	var producers []string
+	for _, depID := range stage.DependsOn {
+		for _, out := range stageMap[depID].Outputs {
+			if matchPattern(out, input) {
+				producers = append(producers, depID)
+				break
+			}
+		}
+	}
+	if strings.Contains(input, "/") || strings.Contains(input, string(os.PathSeparator)) {
+		parts := strings.SplitN(filepath.ToSlash(input), "/", 2)
+		if targetStage, ok := stageMap[parts[0]]; ok {
+			for _, out := range targetStage.Outputs {
+				if matchPattern(out, parts[1]) {
+					producers = append(producers, parts[0])
 					break
 				}
 			}
+		}
+	}
+	return producers
+}
 
-			if !found && (strings.Contains(input, "/") || strings.Contains(input, string(os.PathSeparator))) {
-				parts := strings.SplitN(filepath.ToSlash(input), "/", 2)
-				stageID := parts[0]
-				filename := parts[1]
-
-				if targetStage, ok := stageMap[stageID]; ok {
-					for _, out := range targetStage.Outputs {
-						if matchPattern(out, filename) {
-							found = true
-							break
-						}
+// InputProducers returns the IDs of the protocol stages that declare input
+// as one of their outputs, from the point of view of stage.
+func InputProducers(p domain.Protocol, stage domain.Stage, input string) []string {
+	stageMap := make(map[string]domain.Stage, len(p.Stages))
+	for _, s := range p.Stages {
+		stageMap[s.ID] = s
+	}
+	return inputProducers(stageMap, stage, input)
+}
+
+// dependencyCycles reports each depends_on edge that closes a cycle.
+func dependencyCycles(raw rawProtocol, known map[string]bool) []Diagnostic {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	index := make(map[string]int, len(raw.proto.Stages))
+	for i, stage := range raw.proto.Stages {
+		index[stage.ID] = i
+	}
+	state := make(map[string]int, len(raw.proto.Stages))
+	var diags []Diagnostic
+	var path []string
+
+	var visit func(id string)
+	visit = func(id string) {
+		state[id] = visiting
+		path = append(path, id)
+		i := index[id]
+		stage := raw.proto.Stages[i]
+		for j, dep := range stage.DependsOn {
+			if dep == id || !known[dep] {
+				continue // reported separately
+			}
+			switch state[dep] {
+			case visiting:
+				start := 0
+				for k, p := range path {
+					if p == dep {
+						start = k
+						break
 					}
 				}
+				cycle := append(append([]string{}, path[start:]...), dep)
+				diags = append(diags, raw.stages[i].field("depends_on").item(j).diagnostic(SeverityError, id, "circular dependency detected: %s", strings.Join(cycle, " -> ")))
+			case unvisited:
+				visit(dep)
 			}
+		}
+		path = path[:len(path)-1]
+		state[id] = done
+	}
 
-			if !found {
-				return domain.Protocol{}, fmt.Errorf("stage %q: input %q not found in outputs of any dependency", stage.ID, input)
-			}
+	for _, stage := range raw.proto.Stages {
+		if state[stage.ID] == unvisited {
+			visit(stage.ID)
 		}
 	}
+	return diags
+}
 
-	// Task Prompt Validation
+// protocolWarnings reports constructs that load fine but are probably mistakes.
+func protocolWarnings(raw rawProtocol) []Diagnostic {
+	p := raw.proto
+	var diags []Diagnostic
+	stageMap := make(map[string]domain.Stage, len(p.Stages))
 	for _, stage := range p.Stages {
-		if stage.Type == "task_prompt" && stage.Source != "" {
-			if !seen[stage.Source] {
-				return domain.Protocol{}, fmt.Errorf("stage %q references unknown source stage %q", stage.ID, stage.Source)
+		stageMap[stage.ID] = stage
+	}
+
+	// Unreachable stages: completing a terminal stage ends the workflow, so
+	// anything that (transitively) depends on one can never run.
+	for i, stage := range p.Stages {
+		if terminal := terminalAncestor(stageMap, stage, map[string]bool{}); terminal != "" {
+			diags = append(diags, raw.stages[i].field("id").diagnostic(SeverityWarning, stage.ID, "stage %q is unreachable: it depends on terminal stage %q", stage.ID, terminal))
+		}
+	}
+
+	// Outputs nobody consumes. Review stages read every artifact, and terminal
+	// stages produce the workflow's final deliverables.
+	hasReview := false
+	for _, stage := range p.Stages {
+		if stage.Intent == "review" {
+			hasReview = true
+			break
+		}
+	}
+	if !hasReview {
+		for i, stage := range p.Stages {
+			if stage.Terminal {
+				continue
 			}
-			sourceStage, _ := p.StageByID(stage.Source)
-			if sourceStage.Type != "decompose" {
-				return domain.Protocol{}, fmt.Errorf("stage %q source must be a decompose stage", stage.ID)
+			outputs := raw.stages[i].field("outputs")
+			for j, out := range stage.Outputs {
+				if out != "" && !outputConsumed(p, stage, out) {
+					diags = append(diags, outputs.item(j).diagnostic(SeverityWarning, stage.ID, "output %q of stage %q is not consumed by any stage", out, stage.ID))
+				}
 			}
 		}
 	}
 
-	// Cycle Detection
-	for _, stage := range p.Stages {
-		if err := checkCycles(p, stage.ID, []string{}); err != nil {
-			return domain.Protocol{}, err
+	// Approvals on optional stages may never be satisfiable.
+	for i, approval := range p.Approvals {
+		if stage, ok := stageMap[approval.Stage]; ok && stage.Optional {
+			diags = append(diags, raw.approvals[i].field("stage").diagnostic(SeverityWarning, stage.ID, "approval (role: %s) requires optional stage %q", approval.Role, stage.ID))
 		}
 	}
 
-	// Approval Validation
-	for _, approval := range p.Approvals {
-		if strings.TrimSpace(approval.Stage) == "" {
-			return domain.Protocol{}, fmt.Errorf("approval references empty stage")
+	return diags
+}
+
+// terminalAncestor returns a terminal stage the given stage depends on, if any.
+func terminalAncestor(stageMap map[string]domain.Stage, stage domain.Stage, visited map[string]bool) string {
+	for _, dep := range stage.DependsOn {
+		if visited[dep] {
+			continue
 		}
-		if strings.TrimSpace(approval.Role) == "" {
-			return domain.Protocol{}, fmt.Errorf("approval role is required for stage %q", approval.Stage)
+		visited[dep] = true
+		depStage, ok := stageMap[dep]
+		if !ok {
+			continue
 		}
-		if !seen[approval.Stage] {
-			return domain.Protocol{}, fmt.Errorf("approval references unknown stage %q", approval.Stage)
+		if depStage.Terminal {
+			return dep
+		}
+		if found := terminalAncestor(stageMap, depStage, visited); found != "" {
+			return found
 		}
 	}
+	return ""
+}
 
-	processedCache[abs] = p
-	return p, nil
+// outputConsumed reports whether any stage reads the given output of producer,
+// either as an input or, for decompose stages, as a task_prompt source.
+func outputConsumed(p domain.Protocol, producer domain.Stage, output string) bool {
+	for _, stage := range p.Stages {
+		if stage.ID == producer.ID {
+			continue
+		}
+		if stage.Type == "task_prompt" && stage.Source == producer.ID {
+			return true
+		}
+		for _, input := range stage.Inputs {
+			if matchPattern(output, input) && containsString(stage.DependsOn, producer.ID) {
+				return true
+			}
+			if qualified := producer.ID + "/"; strings.HasPrefix(filepath.ToSlash(input), qualified) && matchPattern(output, strings.TrimPrefix(filepath.ToSlash(input), qualified)) {
+				return true
+			}
+		}
+	}
+	return false
 }
 
-func checkCycles(p domain.Protocol, current string, path []string) error {
-	for _, visited := range path {
-		if visited == current {
-			return fmt.Errorf("circular dependency detected: %v -> %s", path, current)
+func containsString(values []string, want string) bool {
+	for _, v := range values {
+		if v == want {
+			return true
 		}
 	}
-	path = append(path, current)
+	return false
+}
 
-	stage, _ := p.StageByID(current)
-	for _, dep := range stage.DependsOn {
-		if err := checkCycles(p, dep, path); err != nil {
-			return err
+// lintDiagnostics reports severity overrides with unknown levels and invalid
+// custom rules. Rule IDs in overrides are not checked here: they are reported
+// by check.
+func lintDiagnostics(lint *domain.LintConfig, ref nodeRef, stageID string, stages map[string]bool) []Diagnostic {
+	var diags []Diagnostic
+	rulesRef := ref.field("rules")
+	for i, rule := range lint.Rules {
+		if err := rule.Validate(); err != nil {
+			diags = append(diags, rulesRef.item(i).diagnostic(SeverityError, stageID, "lint %v", err))
+		}
+		for j, id := range rule.Stages {
+			if !stages[id] {
+				diags = append(diags, rulesRef.item(i).field("stages").item(j).diagnostic(SeverityError, stageID, "lint rule %s references unknown stage %q", rule.ID, id))
+			}
 		}
 	}
-	return nil
+	rules := make([]string, 0, len(lint.Severity))
+	for rule := range lint.Severity {
+		rules = append(rules, rule)
+	}
+	sort.Strings(rules)
+	for _, rule := range rules {
+		if level := lint.Severity[rule]; !domain.IsLintSeverity(level) {
+			diags = append(diags, ref.field("severity").field(rule).diagnostic(SeverityError, stageID, "lint severity for %q must be error, warning, note or off (got %q)", rule, level))
+		}
+	}
+	return diags
 }
 
 func validateStageID(id string) error {
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"specfirst/internal/domain"
+	"specfirst/internal/utils"
+)
+
+const (
+	PromptsDir = "prompts"
+	RunsDir    = "runs"
+)
+
+// PromptPath returns the content-addressed location of a stored prompt.
+func PromptPath(hash string) string {
+	return GeneratedPath(PromptsDir, strings.TrimPrefix(hash, "sha256:")+".md")
+}
+
+// RunsPath returns a path inside the run log directory.
+func RunsPath(elem ...string) string {
+	parts := append([]string{RunsDir}, elem...)
+	return GeneratedPath(parts...)
+}
+
+// SavePrompt stores a prompt under its content hash and returns the hash.
+// Prompts that are already stored are left untouched.
+func SavePrompt(prompt string) (string, error) {
+	hash := utils.PromptHash(prompt)
+	path := PromptPath(hash)
+	if _, err := os.Stat(path); err == nil {
+		return hash, nil
+	}
+	if err := WriteOutput(path, prompt); err != nil {
+		return "", fmt.Errorf("storing prompt: %w", err)
+	}
+	return hash, nil
+}
+
+// LoadPrompt reads a stored prompt by hash.
+func LoadPrompt(hash string) (string, error) {
+	data, err := os.ReadFile(PromptPath(hash))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("prompt %s is not stored", hash)
+		}
+		return "", err
+	}
+	return string(data), nil
+}
+
+// SaveRun writes a run record and the harness response next to it.
+func SaveRun(run domain.Run, response string) error {
+	if !domain.IsValidSnapshotName(run.ID) {
+		return fmt.Errorf("invalid run id: %s", run.ID)
+	}
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := WriteOutput(RunsPath(run.ID+".json"), string(data)+"\n"); err != nil {
+		return err
+	}
+	return WriteOutput(RunsPath(run.ID+".response.md"), response)
+}
+
+// LoadRun reads a run record and its stored response.
+func LoadRun(id string) (domain.Run, string, error) {
+	if !domain.IsValidSnapshotName(id) {
+		return domain.Run{}, "", fmt.Errorf("invalid run id: %s", id)
+	}
+	data, err := os.ReadFile(RunsPath(id + ".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return domain.Run{}, "", fmt.Errorf("run not found: %s", id)
+		}
+		return domain.Run{}, "", err
+	}
+	var run domain.Run
+	if err := json.Unmarshal(data, &run); err != nil {
+		return domain.Run{}, "", fmt.Errorf("parsing run %s: %w", id, err)
+	}
+	response, err := os.ReadFile(RunsPath(id + ".response.md"))
+	if err != nil && !os.IsNotExist(err) {
+		return domain.Run{}, "", err
+	}
+	return run, string(response), nil
+}
+
+// ListRuns returns all recorded runs, oldest first.
+func ListRuns() ([]domain.Run, error) {
+	matches, err := filepath.Glob(RunsPath("*.json"))
+	if err != nil {
+		return nil, err
+	}
+	runs := make([]domain.Run, 0, len(matches))
+	for _, match := range matches {
+		data, err := os.ReadFile(match)
+		if err != nil {
+			return nil, err
+		}
+		var run domain.Run
+		if err := json.Unmarshal(data, &run); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", match, err)
+		}
+		runs = append(runs, run)
+	}
+	sort.SliceStable(runs, func(i, j int) bool {
+		if runs[i].StartedAt.Equal(runs[j].StartedAt) {
+			return runs[i].ID < runs[j].ID
+		}
+		return runs[i].StartedAt.Before(runs[j].StartedAt)
+	})
+	return runs, nil
+}
+
+// NewRunID returns an unused run id for a stage started at the given time.
+func NewRunID(stageID string, startedAt time.Time) string {
+	base := startedAt.UTC().Format("20060102T150405Z") + "-" + stageID
+	id := base
+	for i := 2; ; i++ {
+		if _, err := os.Stat(RunsPath(id + ".json")); os.IsNotExist(err) {
+			return id
+		}
+		id = fmt.Sprintf("%s-%d", base, i)
+	}
+}
@@ -22,6 +22,8 @@ type Metadata struct {
 	StagesCompleted []string  `json:"stages_completed"`
 	Tags            []string  `json:"tags,omitempty"`
 	Notes           string    `json:"notes,omitempty"`
+
+	StagesSkipped map[string]domain.SkipRecord `json:"stages_skipped,omitempty"`
 }
 
 // CreateParams holds the pre-loaded dependencies for snapshot creation.
@@ -114,6 +116,12 @@ func (r *SnapshotRepository) Create(version string, tags []string, notes string,
 	if err := utils.CopyDir(GeneratedPath(), filepath.Join(tmpRoot, "generated")); err != nil {
 		return err
 	}
+	if err := utils.CopyDir(HistoryPath(), filepath.Join(tmpRoot, HistoryDir)); err != nil {
+		return err
+	}
+	if err := utils.CopyDir(SchemasPath(), filepath.Join(tmpRoot, SchemasDir)); err != nil {
+		return err
+	}
 	if err := utils.CopyDirWithOpts(ProtocolsPath(), filepath.Join(tmpRoot, "protocols"), true); err != nil {
 		return err
 	}
@@ -132,6 +140,7 @@ func (r *SnapshotRepository) Create(version string, tags []string, notes string,
 		Protocol:        proto.Name,
 		ArchivedAt:      time.Now().UTC(),
 		StagesCompleted: s.CompletedStages,
+		StagesSkipped:   s.Skipped,
 		Tags:            tags,
 		Notes:           notes,
 	}
@@ -170,6 +179,8 @@ func (r *SnapshotRepository) Restore(version string, force bool) error {
 	existingPaths := []string{
 		ArtifactsPath(),
 		GeneratedPath(),
+		HistoryPath(),
+		SchemasPath(),
 		ProtocolsPath(),
 		TemplatesPath(),
 		ConfigPath(),
@@ -193,6 +204,12 @@ func (r *SnapshotRepository) Restore(version string, force bool) error {
 	if err := utils.CopyDir(filepath.Join(snapshotRoot, "generated"), filepath.Join(restoreStaging, "generated")); err != nil {
 		return fmt.Errorf("failed to stage generated: %w", err)
 	}
+	if err := utils.CopyDir(filepath.Join(snapshotRoot, HistoryDir), filepath.Join(restoreStaging, HistoryDir)); err != nil {
+		return fmt.Errorf("failed to stage history: %w", err)
+	}
+	if err := utils.CopyDir(filepath.Join(snapshotRoot, SchemasDir), filepath.Join(restoreStaging, SchemasDir)); err != nil {
+		return fmt.Errorf("failed to stage schemas: %w", err)
+	}
 	if err := utils.CopyDirWithOpts(filepath.Join(snapshotRoot, "protocols"), filepath.Join(restoreStaging, "protocols"), true); err != nil {
 		return fmt.Errorf("failed to stage protocols: %w", err)
 	}